@@ -2,7 +2,10 @@ module github.com/Deathstroke72/black-lotus
 
 go 1.24.4
 
-require github.com/anthropics/anthropic-sdk-go v1.26.0
+require (
+	github.com/anthropics/anthropic-sdk-go v1.26.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/tidwall/gjson v1.18.0 // indirect
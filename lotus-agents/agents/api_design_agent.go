@@ -26,7 +26,8 @@ const apiDesignOutputFormat = `Produce these files (every code block MUST start
       → Struct with use case port interface constructor; methods: decode → call use case → encode
 
   internal/interfaces/http/router/router.go
-      → Registers all routes, attaches middleware chain
+      → Registers all routes, attaches middleware chain; also GET /healthz (liveness) and
+        GET /readyz (readiness, pings the database) outside the versioned API prefix
 
 Format: ` + "```go\n// file: internal/interfaces/http/<subdir>/<filename>.go\n<code>\n```" + `
 
@@ -61,6 +62,16 @@ Please produce:
 1. OpenAPI-style godoc comments for each endpoint
 1. Any domain-specific validation rules or constraints`, svc.Prompt())
 
+	if svc.ContractFirst() {
+		prompt += `
+
+CONTRACT-FIRST MODE: the OpenAPI document in the service definition is authoritative.
+- Register exactly the paths and methods it declares in router.go — no more, no fewer
+- Create one DTO struct per component schema, named after the schema, with one field per
+  property and a json tag equal to the property name; add ",omitempty" only for non-required properties
+- Do not rename, add or drop fields, parameters or status codes`
+	}
+
 	if ctx, ok := agentContext["project_context"]; ok {
 		prompt += "\n\nAdditional Context:\n" + ctx
 	}
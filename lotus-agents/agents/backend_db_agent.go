@@ -14,7 +14,8 @@ const backendResponsibilities = `- Implement the domain layer, application layer
 - domain/repository: interfaces declaring data access contracts (not implementations)
 - domain/service: domain services for business logic spanning multiple entities
 - application/usecase: one file per use case; orchestrates domain via repository interfaces
-- application/port: input port interfaces (what HTTP handlers call)
+- application/port: input port interfaces (what HTTP handlers call); use cases reach external APIs only
+  through the output ports the Integrations Agent generated there — never with inline HTTP clients or vendor SDKs
 - infrastructure/postgres/repository: pgx concrete implementations of domain repository interfaces
 - infrastructure/postgres/migration: SQL up/down migration files
 - Dependency Rule: domain and application layers must never import net/http, database/sql, or any Kafka SDK`
@@ -79,6 +80,9 @@ Please produce:
 	if apiDesign, ok := agentContext["api_design"]; ok {
 		prompt += "\n\nAPI Design (implement these contracts):\n" + apiDesign
 	}
+	if integrations, ok := agentContext["integrations"]; ok {
+		prompt += "\n\nOutput ports for external APIs (inject these into the use cases; do not redefine them):\n" + integrations
+	}
 
 	messages := []anthropic.MessageParam{
 		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
@@ -18,6 +18,9 @@ type AgentResult struct {
 	Output    string
 	Artifacts []Artifact
 	Error     error
+
+	// Findings holds structured review findings from reviewing agents
+	Findings []ReviewFinding
 }
 
 // Artifact represents a file or piece of code produced by an agent
@@ -34,6 +37,13 @@ type Agent interface {
 	Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error)
 }
 
+// ArtifactConsumer is implemented by agents that need the full artifacts of
+// earlier stages rather than their truncated output; the pipeline calls
+// UseArtifacts before Run
+type ArtifactConsumer interface {
+	UseArtifacts(artifacts []Artifact)
+}
+
 // BaseAgent provides shared Claude API functionality
 type BaseAgent struct {
 	client       anthropic.Client
@@ -142,21 +152,25 @@ func (b *BaseAgent) Chat(ctx context.Context, messages []anthropic.MessageParam)
 
 }
 
-// ParseArtifacts extracts code blocks from markdown-style output
+// ParseArtifacts extracts code blocks from markdown-style output. As in
+// CommonMark, a block closes only on a fence at least as long as the one that
+// opened it, so a ````markdown block can contain ```mermaid blocks.
 func ParseArtifacts(output string) []Artifact {
 	var artifacts []Artifact
 	lines := strings.Split(output, "\n")
 	var inBlock bool
+	var fenceLen int
 	var lang, filename string
 	var blockLines []string
 
 	for _, line := range lines {
 		if strings.HasPrefix(line, "```") && !inBlock {
 			inBlock = true
-			lang = strings.TrimPrefix(line, "```")
+			fenceLen = len(line) - len(strings.TrimLeft(line, "`"))
+			lang = strings.TrimSpace(line[fenceLen:])
 			filename = ""
 			blockLines = nil
-		} else if line == "```" && inBlock {
+		} else if inBlock && isClosingFence(line, fenceLen) {
 			inBlock = false
 			artifacts = append(artifacts, Artifact{
 				Filename: filename,
@@ -164,13 +178,18 @@ func ParseArtifacts(output string) []Artifact {
 				Content:  strings.Join(blockLines, "\n"),
 			})
 		} else if inBlock {
-			// Detect filename hints like // file: main.go or -- file: migration.sql
-			if strings.HasPrefix(line, "// file:") ||
+			// Detect filename hints like // file: main.go, -- file: migration.sql or <!-- file: README.md -->
+			if filename == "" && (strings.HasPrefix(line, "// file:") ||
 				strings.HasPrefix(line, "# file:") ||
-				strings.HasPrefix(line, "-- file:") {
+				strings.HasPrefix(line, "-- file:") ||
+				strings.HasPrefix(line, "<!-- file:")) {
 				parts := strings.SplitN(line, ":", 2)
 				if len(parts) == 2 {
-					filename = strings.TrimSpace(parts[1])
+					filename = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(parts[1]), "-->"))
+				}
+				// JSON has no comment syntax, so the hint is not kept in the content
+				if lang == "json" {
+					continue
 				}
 			}
 			blockLines = append(blockLines, line)
@@ -179,6 +198,12 @@ func ParseArtifacts(output string) []Artifact {
 	return artifacts
 }
 
+// isClosingFence reports whether line is a bare fence of at least n backticks
+func isClosingFence(line string, n int) bool {
+	line = strings.TrimRight(line, " \t\r")
+	return len(line) >= n && strings.Trim(line, "`") == ""
+}
+
 // ToJSON is a helper to pretty-print structs for context passing
 func ToJSON(v any) string {
 	b, _ := json.MarshalIndent(v, "", "  ")
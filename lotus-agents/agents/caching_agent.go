@@ -0,0 +1,114 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// CacheDir holds the Redis cache decorators
+const CacheDir = "internal/infrastructure/cache/"
+
+const cachingResponsibilities = `- Add a Redis cache in front of the domain repositories without touching the domain or application layers
+- infrastructure/cache/redis: one decorator per cached repository that implements the domain repository
+  interface, wraps the PostgreSQL implementation, and declares var _ repository.<Interface> = (*<Decorator>)(nil)
+- Reads are cache-aside (or read-through for hot lookups); writes go to the inner repository first,
+  then delete the affected keys — never write-through stale entities
+- Key scheme <service>:<entity>:<id>[:v<schema version>]; values are JSON; cache misses and Redis errors
+  fall back to the inner repository and are logged, never surfaced to callers
+- TTLs come from a Config struct loaded from env vars (per entity, with jitter) — no hard-coded durations
+- Stampede protection: golang.org/x/sync/singleflight around loads, plus TTL jitter
+- Invalidation: a handler that deletes keys when domain events the service publishes or consumes change an entity
+- Integration tests use the testcontainers-go Redis module (github.com/testcontainers/testcontainers-go/modules/redis)`
+
+const cachingOutputFormat = `Produce these files (every code block MUST start with // file: <path>):
+
+  internal/infrastructure/cache/config.go                          (Config with TTLs, loaded from env)
+  internal/infrastructure/cache/redis/client.go                    (go-redis client construction, health check)
+  internal/infrastructure/cache/redis/<entity>_repository.go      (one decorator per cached repository)
+  internal/infrastructure/cache/redis/invalidation.go             (event-driven key invalidation)
+  internal/infrastructure/cache/redis/<entity>_repository_integration_test.go
+
+Format: ` + "```go\n// file: internal/infrastructure/cache/<path>.go\n<code>\n```"
+
+// CachingAgent writes Redis cache decorators for any microservice with a cache integration
+type CachingAgent struct {
+	*BaseAgent
+	artifacts []Artifact
+}
+
+func NewCachingAgent(cfg *config.Config, svc *config.ServiceDefinition) *CachingAgent {
+	return &CachingAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Caching Agent", svc, cachingResponsibilities, cachingOutputFormat),
+	}
+}
+
+func (a *CachingAgent) Description() string {
+	return "Wraps domain repositories in Redis cache-aside decorators with TTLs, event-driven invalidation and stampede protection"
+}
+
+// UseArtifacts receives the generated tree so decorators implement the real repository interfaces
+func (a *CachingAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *CachingAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	var purposes []string
+	for _, in := range svc.IntegrationsOfKind(config.KindRedis) {
+		purposes = append(purposes, in.Raw)
+	}
+
+	prompt := fmt.Sprintf(`Add the caching layer for the following microservice:
+
+%s
+
+Cache integrations: %s
+
+Please produce:
+
+1. A decorator for every repository whose data the cache integrations above describe
+1. TTL configuration per cached entity, read from env vars
+1. Invalidation on the domain events below
+1. Integration tests against a Redis testcontainer`, svc.Prompt(), strings.Join(purposes, ", "))
+
+	for _, art := range a.artifacts {
+		if isCacheSource(art.Filename) {
+			prompt += "\n\n" + codeBlock(art)
+		}
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" && art.Language == "go" {
+			artifacts[i].Filename = fmt.Sprintf("%sredis/cache_%d.go", CacheDir, i+1)
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// isCacheSource reports whether a file defines what the cache wraps or reacts to
+func isCacheSource(filename string) bool {
+	if !strings.HasSuffix(filename, ".go") || strings.HasSuffix(filename, "_test.go") {
+		return false
+	}
+	return strings.Contains(filename, "internal/domain/repository/") ||
+		strings.Contains(filename, "internal/domain/entity/") ||
+		strings.Contains(filename, "internal/domain/event/") ||
+		strings.Contains(filename, "kafka/consumer/")
+}
@@ -0,0 +1,124 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// CI definition paths per provider
+const (
+	GitHubWorkflowPath = ".github/workflows/ci.yml"
+	GitLabCIPath       = ".gitlab-ci.yml"
+)
+
+const ciResponsibilities = `- Write the CI definition that builds and verifies the service on every push and merge request
+- Drive every step through the Makefile targets that exist; run a tool directly only when no target covers it
+- Jobs: lint, unit tests, race tests, integration tests with service containers for each declared
+  datastore/broker, a migration check (apply every migration to an empty database, then roll back if supported),
+  and a container image build from the Dockerfile (push only on the default branch)
+- Cache the Go module and build caches; pin action/image versions; least-privilege tokens
+- Integration and migration jobs read connection settings from env vars matching .env.example`
+
+const ciOutputFormat = `Produce exactly one file for the selected CI system (the code block MUST start with # file: <path>):
+
+  GitHub Actions: .github/workflows/ci.yml
+  GitLab CI:      .gitlab-ci.yml
+
+Format: ` + "```yaml\n# file: <path>\n<content>\n```" + `
+
+Do not generate Go code or modify the Makefile.`
+
+// CIAgent writes the CI pipeline definition for any microservice
+type CIAgent struct {
+	*BaseAgent
+	artifacts []Artifact
+}
+
+func NewCIAgent(cfg *config.Config, svc *config.ServiceDefinition) *CIAgent {
+	return &CIAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "CI Agent", svc, ciResponsibilities, ciOutputFormat),
+	}
+}
+
+func (a *CIAgent) Description() string {
+	return "Writes GitHub Actions or GitLab CI jobs for lint, tests, migrations and image build from the Makefile targets"
+}
+
+// UseArtifacts receives the generated tree so the CI jobs can call the real Makefile targets
+func (a *CIAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *CIAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	ciPath, ciName := GitHubWorkflowPath, "GitHub Actions"
+	if svc.CI() == config.CIGitLab {
+		ciPath, ciName = GitLabCIPath, "GitLab CI"
+	}
+
+	prompt := fmt.Sprintf(`Write the %s pipeline (%s) for the following microservice:
+
+%s
+
+Please produce:
+
+1. Jobs for lint, unit tests, race tests, integration tests, migration check and image build
+1. Service containers for: %s
+1. Module/build caching and pinned versions
+
+%s`, ciName, ciPath, svc.Prompt(), backingServices(svc), a.makefileSummary())
+
+	if deployment, ok := agentContext["deployment"]; ok {
+		prompt += "\n\nContainer Build and Environment (Dockerfile, compose and .env.example):\n" + deployment
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" && (art.Language == "yaml" || art.Language == "yml") {
+			artifacts[i].Filename = ciPath
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// makefileSummary lists the targets of the generated Makefile with their recipes
+func (a *CIAgent) makefileSummary() string {
+	for _, art := range a.artifacts {
+		if path.Base(art.Filename) != "Makefile" {
+			continue
+		}
+		mk := spec.ParseMakefile(art.Content)
+		if len(mk.Targets) == 0 {
+			break
+		}
+		var sb strings.Builder
+		sb.WriteString("The generated Makefile declares exactly these targets (call only these with make):\n")
+		for _, t := range mk.Targets {
+			sb.WriteString(fmt.Sprintf("  - %s", t.Name))
+			if len(t.Recipe) > 0 {
+				sb.WriteString(": " + strings.Join(t.Recipe, " && "))
+			}
+			sb.WriteString("\n")
+		}
+		return sb.String()
+	}
+	return "No Makefile was generated; run go and docker commands directly."
+}
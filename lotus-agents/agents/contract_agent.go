@@ -0,0 +1,37 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+)
+
+// ContractAgent stands in for the OpenAPI agent in contract-first mode.
+// It makes no API calls: it places the supplied OpenAPI document into the
+// generated tree as api/openapi.yaml so it ships with the service and is
+// validated like a generated one.
+type ContractAgent struct{}
+
+func NewContractAgent() *ContractAgent { return &ContractAgent{} }
+
+func (a *ContractAgent) Name() string { return "API Contract" }
+
+func (a *ContractAgent) Description() string {
+	return "Uses the supplied OpenAPI document as the authoritative API contract"
+}
+
+func (a *ContractAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	if !svc.ContractFirst() {
+		return nil, fmt.Errorf("[%s] no OpenAPI document supplied", a.Name())
+	}
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    "Contract-first mode: the supplied OpenAPI document is authoritative.\n\n```yaml\n" + svc.OpenAPISpec + "\n```",
+		Artifacts: []Artifact{{
+			Filename: "api/openapi.yaml",
+			Content:  svc.OpenAPISpec,
+			Language: "yaml",
+		}},
+	}, nil
+}
@@ -0,0 +1,111 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+const deploymentResponsibilities = `- Containerize the service and provide a one-command local development stack
+- Dockerfile: multi-stage (builder + minimal runtime such as distroless or alpine), pinned base image tags,
+  CGO_ENABLED=0 static build, runs as a non-root USER, JSON-form ENTRYPOINT/CMD, HEALTHCHECK where the runtime has a shell
+- docker-compose.yml: the service plus EVERY declared datastore and broker, each with a healthcheck;
+  the service depends_on them with condition: service_healthy
+- Run database migrations (and optional seed data) in one-shot init containers that the service waits for
+  with condition: service_completed_successfully
+- All configuration via environment variables, documented in .env.example with safe local defaults;
+  never bake secrets into images or compose files
+- .dockerignore keeping the build context small`
+
+const deploymentOutputFormat = `Produce these files (every code block MUST start with a file comment):
+
+  Dockerfile
+  .dockerignore
+  docker-compose.yml
+  .env.example
+
+Format Dockerfile: ` + "```dockerfile\n# file: Dockerfile\n<content>\n```" + `
+Format compose: ` + "```yaml\n# file: docker-compose.yml\n<content>\n```" + `
+Format env: ` + "```bash\n# file: .env.example\n<content>\n```" + `
+
+Do not generate Go code.`
+
+// DeploymentAgent produces the container image and local dev stack for any microservice
+type DeploymentAgent struct {
+	*BaseAgent
+}
+
+func NewDeploymentAgent(cfg *config.Config, svc *config.ServiceDefinition) *DeploymentAgent {
+	return &DeploymentAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Deployment Agent", svc, deploymentResponsibilities, deploymentOutputFormat),
+	}
+}
+
+func (a *DeploymentAgent) Description() string {
+	return "Writes a multi-stage non-root Dockerfile, docker-compose stack with datastores/brokers, and .env.example"
+}
+
+func (a *DeploymentAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Containerize the following microservice and write its local development stack:
+
+%s
+
+Please produce:
+
+1. A multi-stage Dockerfile building cmd/server into a minimal non-root runtime image
+1. A docker-compose.yml with the service and these backing services: %s
+1. Healthchecks for every container and depends_on conditions so startup is ordered
+1. A migration init container applying internal/infrastructure/postgres/migration before the service starts
+1. A seed init container (profile "seed") for optional sample data
+1. .env.example listing every environment variable the service and compose file read
+1. A .dockerignore`, svc.Prompt(), backingServices(svc))
+
+	if backend, ok := agentContext["backend_db"]; ok {
+		prompt += "\n\nBackend Layer (migrations and configuration to account for):\n" + backend
+	}
+	if testing, ok := agentContext["testing_security"]; ok {
+		prompt += "\n\nServer Wiring and Makefile (entrypoint, ports and env vars):\n" + testing
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" {
+			switch strings.ToLower(art.Language) {
+			case "dockerfile", "docker":
+				artifacts[i].Filename = "Dockerfile"
+			case "yaml", "yml":
+				artifacts[i].Filename = "docker-compose.yml"
+			case "bash", "sh", "env", "dotenv":
+				artifacts[i].Filename = ".env.example"
+			}
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// backingServices lists the datastores and brokers declared in the definition
+func backingServices(svc *config.ServiceDefinition) string {
+	kinds := svc.InfrastructureKinds()
+	if len(kinds) == 0 {
+		return "none declared"
+	}
+	return strings.Join(kinds, ", ")
+}
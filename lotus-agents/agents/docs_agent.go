@@ -0,0 +1,128 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Documentation paths in the generated tree
+const (
+	DocsReadmePath       = "README.md"
+	DocsArchitecturePath = "docs/architecture.md"
+	DocsADRDir           = "docs/adr/"
+	DocsRunbookPath      = "docs/runbook.md"
+	DocsAPIGuidePath     = "docs/api-guide.md"
+)
+
+// docsMaxInputChars bounds the source text sent to the agent; the remaining
+// files are listed by name only
+const docsMaxInputChars = 120_000
+
+const docsResponsibilities = `- Document the service as it was actually generated: every command, env var, port, endpoint
+  and topic you mention must appear in the files below; never invent one
+- README: what the service does, prerequisites, how to run locally (compose), configure (env vars),
+  test (unit, integration, contract, load) and deploy, using the real Makefile targets
+- Architecture: C4 context and container diagrams in Mermaid (C4Context / C4Container), then the
+  Clean Architecture layers and how a request and an event flow through them
+- ADRs (Michael Nygard format: Status, Context, Decision, Consequences), one per significant choice visible
+  in the code: datastore, transactional outbox and idempotent consumers, API styles, auth, deployment
+- Runbook: symptoms, diagnosis (metrics, logs, queries) and remediation for the incidents this service can
+  have — DLQ growth, consumer lag, migration failures, datastore outage, elevated error rate
+- API guide: authentication, then worked request/response examples for the main flows drawn from the contract`
+
+const docsOutputFormat = `Produce these files. Wrap each in a FOUR-backtick fence so Mermaid blocks can be nested,
+and start each with its file comment:
+
+  README.md
+  docs/architecture.md
+  docs/adr/0001-<decision>.md, docs/adr/0002-<decision>.md, ...
+  docs/runbook.md
+  docs/api-guide.md
+
+Format: ` + "````markdown\n<!-- file: docs/<name>.md -->\n<content>\n````"
+
+// DocsAgent writes the README, architecture docs, ADRs, runbook and API guide for any microservice
+type DocsAgent struct {
+	*BaseAgent
+	artifacts []Artifact
+}
+
+func NewDocsAgent(cfg *config.Config, svc *config.ServiceDefinition) *DocsAgent {
+	return &DocsAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Documentation Agent", svc, docsResponsibilities, docsOutputFormat),
+	}
+}
+
+func (a *DocsAgent) Description() string {
+	return "Writes the README, C4 diagrams, ADRs, an incident runbook and an API usage guide from the generated files"
+}
+
+// UseArtifacts receives the generated tree the documentation describes
+func (a *DocsAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *DocsAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Write the documentation for the following microservice:\n\n%s\n", svc.Prompt()))
+	sb.WriteString(fmt.Sprintf("Backing services: %s\n\nKey generated files:\n\n", backingServices(svc)))
+
+	budget := docsMaxInputChars
+	var files []string
+	for _, art := range a.artifacts {
+		if art.Filename == "" {
+			continue
+		}
+		files = append(files, art.Filename)
+		if !isDocsSource(art.Filename) || len(art.Content) > budget {
+			continue
+		}
+		budget -= len(art.Content)
+		sb.WriteString(codeBlock(art) + "\n\n")
+	}
+	sb.WriteString("All generated files:\n")
+	for _, f := range files {
+		sb.WriteString("  - " + f + "\n")
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(sb.String())),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" && (art.Language == "markdown" || art.Language == "md") {
+			artifacts[i].Filename = fmt.Sprintf("docs/doc_%d.md", i+1)
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// isDocsSource reports whether a file carries facts the documentation must
+// reflect: how to build, run, configure and call the service
+func isDocsSource(filename string) bool {
+	switch path.Base(filename) {
+	case "Makefile", "docker-compose.yml", ".env.example", "Dockerfile", "main.go", "router.go", "plan.json":
+		return true
+	}
+	return strings.HasSuffix(filename, "api/openapi.yaml") ||
+		strings.HasSuffix(filename, ".proto") ||
+		strings.HasSuffix(filename, ".sql") ||
+		strings.Contains(filename, "internal/domain/event/") ||
+		strings.Contains(filename, "deploy/k8s/base/") ||
+		strings.HasSuffix(filename, ThreatModelPath)
+}
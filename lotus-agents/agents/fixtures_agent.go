@@ -0,0 +1,145 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Seed and fixture locations in the generated tree
+const (
+	MigrationDir = "internal/infrastructure/postgres/migration/"
+	SeedDir      = "internal/infrastructure/postgres/seed/"
+	FixtureDir   = "internal/testutil/fixture/"
+)
+
+const fixturesResponsibilities = `- Give developers a populated local database and tests a concise way to build valid data
+- Seed SQL: realistic sample data for this domain (plausible names, amounts, statuses and timestamps — no lorem ipsum),
+  a handful of rows per table, written against the migrations below and nothing else
+- Every INSERT lists its columns, supplies every NOT NULL column without a default, and uses explicit,
+  stable primary keys (fixed UUIDs or integers) so other rows can reference them
+- Seed files are numbered in foreign-key order: a referenced row is always inserted by an earlier statement
+- Seeds are idempotent: every INSERT ends with ON CONFLICT DO NOTHING so make seed can be re-run
+- Go fixture builders (internal/testutil/fixture): one builder per entity returning a valid domain entity with
+  sensible defaults and With<Field> options, plus an Insert helper that persists it through the repository
+- Makefile seed target: the Testing & Security Agent adds it; tell it the exact command in a short note`
+
+const fixturesOutputFormat = `Produce these files (every code block MUST start with -- file: or // file:):
+
+  internal/infrastructure/postgres/seed/<NNN>_<table>.sql    (numbered in foreign-key order)
+  internal/testutil/fixture/<entity>.go                       (one builder per entity)
+
+Then a plain-text line outside the code blocks: "make seed: <command>" applying the seed files in order with psql "$DATABASE_URL".
+
+Format SQL: ` + "```sql\n-- file: internal/infrastructure/postgres/seed/<NNN>_<table>.sql\n<sql>\n```" + `
+Format Go: ` + "```go\n// file: internal/testutil/fixture/<entity>.go\n<code>\n```"
+
+// FixturesAgent writes seed SQL and Go fixture builders for any microservice
+type FixturesAgent struct {
+	*BaseAgent
+	artifacts   []Artifact
+	seedCommand string
+}
+
+func NewFixturesAgent(cfg *config.Config, svc *config.ServiceDefinition) *FixturesAgent {
+	return &FixturesAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Fixtures Agent", svc, fixturesResponsibilities, fixturesOutputFormat),
+	}
+}
+
+func (a *FixturesAgent) Description() string {
+	return "Writes realistic seed SQL in foreign-key order and Go test fixture builders consistent with the migrations"
+}
+
+// SeedCommand returns the "make seed:" command of the last Run, or ""
+func (a *FixturesAgent) SeedCommand() string { return a.seedCommand }
+
+// UseArtifacts receives the generated tree so seeds and builders match the real schema and entities
+func (a *FixturesAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *FixturesAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Write seed data and test fixtures for the following microservice:
+
+%s
+
+Please produce:
+
+1. Seed SQL for every table the migrations below create
+1. A fixture builder for every domain entity below
+1. The make seed command`, svc.Prompt())
+
+	for _, art := range a.artifacts {
+		if isFixtureSource(art.Filename) {
+			prompt += "\n\n" + codeBlock(art)
+		}
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	a.seedCommand = parseSeedCommand(output)
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" {
+			switch art.Language {
+			case "sql":
+				artifacts[i].Filename = fmt.Sprintf("%s%03d_seed.sql", SeedDir, i+1)
+			case "go":
+				artifacts[i].Filename = fmt.Sprintf("%sfixture_%d.go", FixtureDir, i+1)
+			}
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// parseSeedCommand returns the command of the last "make seed: <command>"
+// line outside code blocks
+func parseSeedCommand(output string) string {
+	cmd := ""
+	inBlock := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inBlock = !inBlock
+			continue
+		}
+		if inBlock {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(strings.TrimLeft(line, "`\"*- "), "make seed:"); ok {
+			cmd = strings.Trim(strings.TrimLeft(rest, "* "), "` ")
+		}
+	}
+	return cmd
+}
+
+// isFixtureSource reports whether a file defines the schema or entities fixtures must match
+func isFixtureSource(filename string) bool {
+	if strings.HasSuffix(filename, "_test.go") {
+		return false
+	}
+	return strings.Contains(filename, MigrationDir) && strings.HasSuffix(filename, ".sql") && !IsDownMigration(filename) ||
+		strings.Contains(filename, "internal/domain/entity/") && strings.HasSuffix(filename, ".go") ||
+		strings.Contains(filename, "internal/domain/repository/") && strings.HasSuffix(filename, ".go")
+}
+
+// IsDownMigration reports whether a migration file reverts a schema change
+func IsDownMigration(filename string) bool {
+	base := strings.TrimSuffix(filename, ".sql")
+	return strings.HasSuffix(base, "_down") || strings.HasSuffix(base, ".down")
+}
@@ -0,0 +1,113 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+const graphqlResponsibilities = `- Design the GraphQL interface layer (Clean Architecture: interfaces/graphql) for BFF consumers
+- Write an SDL schema derived from the domain entities (object types) and business operations
+  (queries for reads, mutations for state changes) with input types and payload types per mutation
+- Write gqlgen-style resolvers that call the application port interfaces (internal/application/port) —
+  zero business logic in resolvers; map port outputs to GraphQL models
+- Protect every relation field against N+1 queries with per-request dataloaders (batch + cache)
+- Declare and enforce schema-level authorization with an @auth(requires: Role) directive backed by the JWT claims
+- Limit query depth and complexity; never expose domain entities directly as GraphQL models`
+
+const graphqlOutputFormat = `Produce these files (every code block MUST start with a file comment):
+
+  api/graphql/schema.graphqls
+      → SDL: directive @auth, enum Role, scalars, types, inputs, Query, Mutation
+
+  gqlgen.yml
+      → schema: api/graphql/*.graphqls, model/resolver output under internal/interfaces/graphql
+
+  internal/interfaces/graphql/resolver/resolver.go
+      → Resolver struct holding the application port interfaces (dependency injection root)
+
+  internal/interfaces/graphql/resolver/schema.resolvers.go
+      → Query/Mutation/field resolvers; field resolvers for relations use dataloaders
+
+  internal/interfaces/graphql/dataloader/loaders.go
+      → Loaders struct, per-request middleware, batch functions calling application ports
+
+  internal/interfaces/graphql/directive/auth.go
+      → @auth directive implementation reading roles from the request context
+
+  internal/interfaces/graphql/server.go
+      → handler.New with directives, complexity limit, depth limit, dataloader middleware
+
+Format SDL: ` + "```graphql\n# file: api/graphql/schema.graphqls\n<sdl>\n```" + `
+Format Go: ` + "```go\n// file: internal/interfaces/graphql/<subdir>/<filename>.go\n<code>\n```" + `
+
+Do not generate gqlgen output (generated.go, models_gen.go); it is produced by go run github.com/99designs/gqlgen.`
+
+// GraphQLAgent designs a GraphQL schema and resolver layer for any microservice
+type GraphQLAgent struct {
+	*BaseAgent
+}
+
+func NewGraphQLAgent(cfg *config.Config, svc *config.ServiceDefinition) *GraphQLAgent {
+	return &GraphQLAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "GraphQL Interface Agent", svc, graphqlResponsibilities, graphqlOutputFormat),
+	}
+}
+
+func (a *GraphQLAgent) Description() string {
+	return "Writes the GraphQL SDL schema, gqlgen resolvers over the application ports, dataloaders and @auth directives"
+}
+
+func (a *GraphQLAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Design and implement the GraphQL interface for the following microservice:
+
+%s
+
+Please produce:
+
+1. An SDL schema with an object type per entity, a query per read operation and a mutation per state-changing operation
+1. Input and payload types for every mutation (payloads carry the result plus user-facing errors)
+1. A directive @auth(requires: Role!) on FIELD_DEFINITION | OBJECT, applied to every mutation and sensitive field
+1. gqlgen configuration and resolvers that delegate to the application port interfaces
+1. Dataloaders for every relation between entities so list queries issue one batched call per relation
+1. Server setup with query complexity and depth limits`, svc.Prompt())
+
+	if backend, ok := agentContext["backend_db"]; ok {
+		prompt += "\n\nApplication Layer (resolvers must call these port interfaces):\n" + backend
+	}
+	if api, ok := agentContext["api_design"]; ok {
+		prompt += "\n\nREST API Design (keep naming and semantics consistent with these endpoints):\n" + api
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" {
+			switch art.Language {
+			case "graphql", "gql":
+				artifacts[i].Filename = "api/graphql/schema.graphqls"
+			case "yaml", "yml":
+				artifacts[i].Filename = "gqlgen.yml"
+			case "go":
+				artifacts[i].Filename = fmt.Sprintf("internal/interfaces/graphql/resolver/graphql_%d.go", i+1)
+			}
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
@@ -0,0 +1,123 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+const grpcResponsibilities = `- Design the gRPC interface layer (Clean Architecture: interfaces/grpc) alongside or instead of REST
+- Write proto3 .proto files derived from the domain entities and business operations
+- Follow buf lint (STANDARD) conventions: versioned package (<service>.v1), PascalCase messages and RPCs,
+  lower_snake_case fields, UPPER_SNAKE_CASE enum values prefixed with the enum name and a *_UNSPECIFIED zero value,
+  one <Rpc>Request/<Rpc>Response pair per RPC, services suffixed with "Service"
+- Write gRPC server implementations that call the SAME application port interfaces the HTTP handlers use
+  (internal/application/port) — zero business logic in the server
+- Map protobuf messages <-> application inputs/outputs in a dedicated mapper; never expose domain entities on the wire
+- Write unary and stream interceptors for JWT auth and request-id propagation via gRPC metadata
+- Translate application errors to gRPC status codes (NotFound, InvalidArgument, FailedPrecondition, ...)`
+
+const grpcOutputFormat = `Produce these files (every code block MUST start with // file: <path>, or # file: <path> for YAML):
+
+  api/proto/<package_path>/<service>.proto
+      → proto3; package <service>.v1; option go_package; one service, request/response messages, enums
+
+  buf.yaml
+      → version: v2, modules path api/proto, lint STANDARD, breaking FILE
+
+  internal/interfaces/grpc/server/<service>_server.go
+      → Implements the generated <Service>Server; depends only on application port interfaces
+
+  internal/interfaces/grpc/mapper/<entity>_mapper.go
+      → Converts protobuf messages <-> application port inputs/outputs
+
+  internal/interfaces/grpc/interceptor/auth_interceptor.go
+  internal/interfaces/grpc/interceptor/request_id_interceptor.go
+      → Unary + stream interceptors
+
+  internal/interfaces/grpc/server.go
+      → Builds the *grpc.Server with interceptor chain, health service and reflection
+
+Format proto: ` + "```proto\n// file: api/proto/<package_path>/<service>.proto\n<proto>\n```" + `
+Format Go: ` + "```go\n// file: internal/interfaces/grpc/<subdir>/<filename>.go\n<code>\n```" + `
+Format buf: ` + "```yaml\n# file: buf.yaml\n<config>\n```" + `
+
+Do not generate protoc output (*.pb.go); it is produced by buf generate.`
+
+// GRPCAgent designs protobuf contracts and the gRPC interface layer for any microservice
+type GRPCAgent struct {
+	*BaseAgent
+}
+
+func NewGRPCAgent(cfg *config.Config, svc *config.ServiceDefinition) *GRPCAgent {
+	return &GRPCAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "gRPC Interface Agent", svc, grpcResponsibilities, grpcOutputFormat),
+	}
+}
+
+func (a *GRPCAgent) Description() string {
+	return "Writes .proto contracts, gRPC servers over the application ports, and auth/request-id interceptors"
+}
+
+func (a *GRPCAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	pkg := ProtoPackage(svc)
+	prompt := fmt.Sprintf(`Design and implement the gRPC interface for the following microservice:
+
+%s
+
+Please produce:
+
+1. A proto3 file with package %q containing one service with an RPC per business operation
+1. Request/response messages for every RPC and messages for the entities they return
+1. Enums for entity states, with a zero *_UNSPECIFIED value
+1. google.protobuf.Timestamp for times and string IDs; pagination (page_size/page_token) on list RPCs
+1. A gRPC server implementation that delegates to the application port interfaces
+1. Auth (JWT from "authorization" metadata) and request-id interceptors, unary and stream
+1. Error mapping from application errors to gRPC status codes
+1. A buf.yaml configured for STANDARD lint rules`, svc.Prompt(), pkg)
+
+	if backend, ok := agentContext["backend_db"]; ok {
+		prompt += "\n\nApplication Layer (the gRPC server must call these port interfaces):\n" + backend
+	}
+	if api, ok := agentContext["api_design"]; ok {
+		prompt += "\n\nREST API Design (keep RPC semantics consistent with these endpoints):\n" + api
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" {
+			switch art.Language {
+			case "proto", "protobuf":
+				artifacts[i].Filename = fmt.Sprintf("api/proto/%s/%s.proto", strings.ReplaceAll(pkg, ".", "/"), strings.Split(pkg, ".")[0])
+			case "go":
+				artifacts[i].Filename = fmt.Sprintf("internal/interfaces/grpc/server/grpc_%d.go", i+1)
+			}
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// ProtoPackage returns the versioned protobuf package for a service,
+// e.g. "inventory-java" → "inventory_java.v1"
+func ProtoPackage(svc *config.ServiceDefinition) string {
+	name := strings.ToLower(strings.NewReplacer("-", "_", " ", "_", ".", "_").Replace(svc.Name))
+	return name + ".v1"
+}
@@ -0,0 +1,96 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// IntegrationDir holds one client adapter package per external API
+const IntegrationDir = "internal/infrastructure/integration/"
+
+const integrationsResponsibilities = `- Isolate every external API (vendor or other service) behind an output port so use cases never call it directly
+- application/port: one output port interface per dependency, named as in the plan, using request/result
+  types declared next to it — no vendor SDK or net/http types cross the port
+- infrastructure/integration/<package>: the adapter implementing the port, declaring
+  var _ port.<Interface> = (*Client)(nil); base URL, credentials and timeouts come from a Config loaded from env vars
+- Resilience: an http.Client with an explicit Timeout, per-call context deadlines, retries with exponential
+  backoff and jitter for idempotent or idempotency-keyed calls only, and a circuit breaker (github.com/sony/gobreaker)
+- Vendor errors are mapped to port-level sentinel errors (ErrUnavailable, ErrRejected, ...); never log secrets or PII
+- infrastructure/integration/<package>/fake: an httptest server that mimics the vendor endpoints the adapter
+  uses, with switches for failures and latency; adapter tests run against it`
+
+const integrationsOutputFormat = `Produce these files (every code block MUST start with // file: <path>):
+
+  internal/application/port/<dependency>_gateway.go                (one output port per dependency)
+  internal/infrastructure/integration/<package>/config.go
+  internal/infrastructure/integration/<package>/client.go          (adapter: timeouts, retries, circuit breaker)
+  internal/infrastructure/integration/<package>/fake/server.go     (httptest fake of the vendor API)
+  internal/infrastructure/integration/<package>/client_test.go     (adapter tests against the fake)
+
+Format: ` + "```go\n// file: internal/<layer>/<path>.go\n<code>\n```"
+
+// IntegrationsAgent writes output ports, resilient client adapters and fake servers for any microservice's external APIs
+type IntegrationsAgent struct {
+	*BaseAgent
+}
+
+func NewIntegrationsAgent(cfg *config.Config, svc *config.ServiceDefinition) *IntegrationsAgent {
+	return &IntegrationsAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Integrations Agent", svc, integrationsResponsibilities, integrationsOutputFormat),
+	}
+}
+
+func (a *IntegrationsAgent) Description() string {
+	return "Puts each external API behind an output port with a resilient client adapter and a fake server for tests"
+}
+
+func (a *IntegrationsAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	var deps []string
+	for _, in := range svc.ExternalAPIs() {
+		deps = append(deps, fmt.Sprintf("  - %s → %s%s", in.Raw, IntegrationDir, in.Package()))
+	}
+
+	prompt := fmt.Sprintf(`Write the outbound integrations for the following microservice:
+
+%s
+
+External APIs and their adapter packages:
+%s
+
+Please produce, for every external API above:
+
+1. The output port interface the use cases will depend on
+1. A client adapter with timeouts, retries and a circuit breaker
+1. A fake server for tests and adapter tests against it`, svc.Prompt(), strings.Join(deps, "\n"))
+
+	if apiDesign, ok := agentContext["api_design"]; ok {
+		prompt += "\n\nAPI Design (from API Design Agent):\n" + apiDesign
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" && art.Language == "go" {
+			artifacts[i].Filename = fmt.Sprintf("%sintegration_%d.go", IntegrationDir, i+1)
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
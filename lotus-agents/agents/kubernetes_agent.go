@@ -0,0 +1,126 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+const kubernetesResponsibilities = `- Produce the Kubernetes deployment as a Kustomize base plus per-environment overlays (plain YAML, no templating)
+- Deployment: readiness and liveness probes hitting the health endpoints the service registers,
+  CPU/memory requests and a memory limit, runAsNonRoot with a read-only root filesystem, non-secret
+  config from the ConfigMap via envFrom, every secret via secretKeyRef to an externally managed Secret
+- Service, HorizontalPodAutoscaler (autoscaling/v2), PodDisruptionBudget (policy/v1) and ServiceAccount
+- NetworkPolicy (networking.k8s.io/v1) restricting ingress to the service port and egress to DNS plus
+  exactly the datastores, brokers and services the definition integrates with
+- Consistent app.kubernetes.io labels; every selector matches the pod template labels
+- Never write Secret manifests with real values; document the expected Secret name and keys instead`
+
+const kubernetesOutputFormat = `Produce these files (every code block MUST start with # file: <path>):
+
+  deploy/k8s/base/kustomization.yaml      → lists every base manifest under resources
+  deploy/k8s/base/deployment.yaml
+  deploy/k8s/base/service.yaml
+  deploy/k8s/base/serviceaccount.yaml
+  deploy/k8s/base/configmap.yaml
+  deploy/k8s/base/hpa.yaml
+  deploy/k8s/base/pdb.yaml
+  deploy/k8s/base/networkpolicy.yaml
+
+  deploy/k8s/overlays/staging/kustomization.yaml     → resources: [../../base], patches, image tag
+  deploy/k8s/overlays/production/kustomization.yaml
+  deploy/k8s/overlays/<env>/<patch>.yaml              → strategic-merge patches (replicas, resources)
+
+Format: ` + "```yaml\n# file: deploy/k8s/base/<filename>.yaml\n<manifest>\n```" + `
+
+Do not generate Helm templates or Go code.`
+
+// KubernetesAgent produces Kubernetes manifests for any microservice
+type KubernetesAgent struct {
+	*BaseAgent
+}
+
+func NewKubernetesAgent(cfg *config.Config, svc *config.ServiceDefinition) *KubernetesAgent {
+	return &KubernetesAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Kubernetes Agent", svc, kubernetesResponsibilities, kubernetesOutputFormat),
+	}
+}
+
+func (a *KubernetesAgent) Description() string {
+	return "Writes a Kustomize base and overlays: Deployment, Service, HPA, PDB, ConfigMap and NetworkPolicy"
+}
+
+func (a *KubernetesAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Write the Kubernetes manifests for the following microservice:
+
+%s
+
+Please produce:
+
+1. A Kustomize base with Deployment, Service, ServiceAccount, ConfigMap, HorizontalPodAutoscaler,
+   PodDisruptionBudget and NetworkPolicy
+1. Readiness probe on GET /readyz and liveness probe on GET /healthz%s
+1. A NetworkPolicy allowing egress to DNS (UDP/TCP 53) and: %s
+1. secretKeyRef entries for every credential the service reads (database URL, broker credentials, JWT keys)
+1. staging and production overlays adjusting replicas, HPA bounds and resources`, svc.Prompt(), grpcProbeNote(svc), egressTargets(svc))
+
+	if deployment, ok := agentContext["deployment"]; ok {
+		prompt += "\n\nContainer Image and Environment (Dockerfile, compose and .env.example):\n" + deployment
+	}
+	if testing, ok := agentContext["testing_security"]; ok {
+		prompt += "\n\nServer Wiring (ports and health endpoints):\n" + testing
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" && (art.Language == "yaml" || art.Language == "yml") {
+			artifacts[i].Filename = fmt.Sprintf("deploy/k8s/base/manifest_%d.yaml", i+1)
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+// grpcProbeNote asks for a native gRPC probe on the gRPC port when the service exposes one
+func grpcProbeNote(svc *config.ServiceDefinition) string {
+	if !svc.Exposes(config.InterfaceGRPC) {
+		return ""
+	}
+	if !svc.Exposes(config.InterfaceREST) {
+		return " — this service has no HTTP API, so use native grpc probes against the gRPC health service instead"
+	}
+	return " (also expose the gRPC port on the Service)"
+}
+
+// egressTargets lists the declared integrations with their ports for the NetworkPolicy
+func egressTargets(svc *config.ServiceDefinition) string {
+	var targets []string
+	for _, in := range svc.ParsedIntegrations() {
+		if port := in.DefaultPort(); port != 0 {
+			targets = append(targets, fmt.Sprintf("%s (TCP %d)", in.Name, port))
+		} else {
+			targets = append(targets, in.Name)
+		}
+	}
+	if len(targets) == 0 {
+		return "nothing else"
+	}
+	return strings.Join(targets, ", ")
+}
@@ -0,0 +1,104 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// LoadTestDir holds the generated k6 scripts
+const LoadTestDir = "loadtest/k6/"
+
+const loadTestResponsibilities = `- Write k6 load tests that establish a performance baseline for every REST endpoint
+- One scenario per endpoint group, with a ramp-up, steady-state and ramp-down profile (ramping-arrival-rate)
+- Realistic payloads built from the entity schemas in the API contract: valid formats, enums, ranges and
+  unique IDs per iteration; create resources in setup() before reading, updating or deleting them
+- Tag every request with {name: "<operationId>"} and set thresholds per endpoint on
+  http_req_duration{name:<operationId>} (p95, p99) and http_req_failed{name:<operationId>}
+- Obtain a JWT the same way the generated JWT middleware verifies it (algorithm, issuer, audience, claims, roles)
+  and send it as "Authorization: Bearer <token>"; tokens come from env vars, never hard-coded secrets
+- Read the base URL and credentials from __ENV with sensible local defaults`
+
+const loadTestOutputFormat = `Produce these files (every code block MUST start with // file: <path>):
+
+  loadtest/k6/lib/auth.js        (token acquisition / signing helpers)
+  loadtest/k6/lib/payloads.js    (payload builders per entity)
+  loadtest/k6/<scenario>.js      (one per endpoint group, each exporting options with scenarios and thresholds)
+  loadtest/k6/README.md          (how to run, env vars, how to read the thresholds)
+
+Format: ` + "```javascript\n// file: loadtest/k6/<name>.js\n<code>\n```" + `
+
+Do not generate Go code.`
+
+// LoadTestAgent writes k6 load-test scenarios for any microservice
+type LoadTestAgent struct {
+	*BaseAgent
+	artifacts []Artifact
+}
+
+func NewLoadTestAgent(cfg *config.Config, svc *config.ServiceDefinition) *LoadTestAgent {
+	return &LoadTestAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Load Test Agent", svc, loadTestResponsibilities, loadTestOutputFormat),
+	}
+}
+
+func (a *LoadTestAgent) Description() string {
+	return "Writes k6 load tests per endpoint with realistic payloads, ramp-up profiles, thresholds and JWT handling"
+}
+
+// UseArtifacts receives the generated tree so scripts follow the real contract and JWT middleware
+func (a *LoadTestAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *LoadTestAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Write k6 load tests for the following microservice:
+
+%s
+
+Please produce:
+
+1. A scenario for every endpoint in the API contract below, tagged with its operationId
+1. Per-endpoint thresholds on latency (p95, p99) and error rate
+1. Payload builders producing realistic, valid data for each entity
+1. Token handling compatible with the JWT middleware below`, svc.Prompt())
+
+	for _, art := range a.artifacts {
+		if strings.HasSuffix(art.Filename, "api/openapi.yaml") || isJWTMiddleware(art.Filename) {
+			prompt += "\n\n" + codeBlock(art)
+		}
+	}
+	if api, ok := agentContext["api_design"]; ok {
+		prompt += "\n\nAPI Design (from API Design Agent):\n" + api
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" && (art.Language == "javascript" || art.Language == "js") {
+			artifacts[i].Filename = fmt.Sprintf("%sscenario_%d.js", LoadTestDir, i+1)
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
+
+func isJWTMiddleware(filename string) bool {
+	base := strings.ToLower(path.Base(filename))
+	return strings.Contains(filename, "middleware") && strings.Contains(base, "jwt") && strings.HasSuffix(base, ".go")
+}
@@ -21,6 +21,10 @@ const messagingOutputFormat = `Produce these files (every code block MUST start
 
   internal/domain/event/<event_name>_event.go
       → Pure struct: EventID, CorrelationID, Timestamp, Version, payload fields; zero SDK imports
+      → json tags on every field; directly above each event struct, after its doc comment:
+          //asyncapi:topic <service>.<event-name>.v<version>
+          //asyncapi:direction send|receive
+          //asyncapi:version <version>
 
   internal/infrastructure/kafka/producer/outbox_publisher.go
       → Reads outbox table rows, publishes to Kafka, marks as published
@@ -74,6 +78,9 @@ Please produce:
 	if backend, ok := agentContext["backend_db"]; ok {
 		prompt += "\n\nDatabase/Service Context (outbox table should align with this schema):\n" + backend
 	}
+	if obs, ok := agentContext["observability"]; ok {
+		prompt += "\n\nObservability (inject/extract trace context with the Kafka header carrier in producers and consumers):\n" + obs
+	}
 
 	messages := []anthropic.MessageParam{
 		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
@@ -0,0 +1,102 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+const observabilityResponsibilities = `- Instrument the service with OpenTelemetry tracing, Prometheus RED metrics and slog structured logging
+- internal/infrastructure/observability: one Setup function that installs the OTel TracerProvider
+  (OTLP exporter, service.name/version resource), the W3C TraceContext + Baggage propagator, and the
+  Prometheus registry; returns a shutdown func that flushes spans
+- slog JSON logger whose handler adds trace_id, span_id and correlation_id from the context to every record
+- RED metrics per use case (requests total by outcome, duration histogram) via a decorator the wiring
+  applies to every use case; HTTP server metrics and spans via middleware; /metrics handler
+- Kafka propagation: inject traceparent/tracestate into message headers next to the correlation_id
+  header on publish, extract them on consume and start a consumer span linked to the producer
+- A starter Grafana dashboard whose queries only use metric names this agent registers
+- The observability package must not import application or domain packages`
+
+const observabilityOutputFormat = `Produce these files (every code block MUST start with // file: <path>):
+
+  internal/infrastructure/observability/telemetry.go
+      → Setup(ctx, Config) (shutdown func(context.Context) error, err error)
+  internal/infrastructure/observability/logging.go
+      → NewLogger(...) *slog.Logger; context-aware handler adding trace and correlation IDs
+  internal/infrastructure/observability/metrics.go
+      → Metric definitions and the use case RED decorator
+  internal/infrastructure/observability/kafka_carrier.go
+      → propagation.TextMapCarrier over Kafka headers; InjectKafkaHeaders / ExtractKafkaHeaders
+  internal/interfaces/http/middleware/tracing_middleware.go
+      → Server spans, HTTP RED metrics, route-pattern span names
+
+  deploy/grafana/dashboard.json
+      → Rate, errors and duration per use case and per HTTP route; consumer lag if Kafka is used
+
+Format Go: ` + "```go\n// file: internal/infrastructure/observability/<filename>.go\n<code>\n```" + `
+Format dashboard: ` + "```json\n// file: deploy/grafana/dashboard.json\n<json>\n```"
+
+// ObservabilityAgent adds tracing, metrics and structured logging to any microservice
+type ObservabilityAgent struct {
+	*BaseAgent
+}
+
+func NewObservabilityAgent(cfg *config.Config, svc *config.ServiceDefinition) *ObservabilityAgent {
+	return &ObservabilityAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Observability Agent", svc, observabilityResponsibilities, observabilityOutputFormat),
+	}
+}
+
+func (a *ObservabilityAgent) Description() string {
+	return "Adds OpenTelemetry tracing, Prometheus RED metrics, slog logging, Kafka trace propagation and a Grafana dashboard"
+}
+
+func (a *ObservabilityAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Add observability to the following microservice:
+
+%s
+
+Please produce:
+
+1. The observability package: OTel SDK bootstrap, propagators, Prometheus registry and shutdown
+1. A slog logger that correlates every log line with the active trace and the request's CorrelationID
+1. RED metrics for each use case implementing the operations above, applied as a decorator
+1. HTTP tracing/metrics middleware using the route pattern (not the raw URL) as span name and label
+1. Kafka header carrier so producers inject and consumers extract trace context alongside correlation_id
+1. A Grafana dashboard JSON (schemaVersion 39, Prometheus datasource variable) for these metrics`, svc.Prompt())
+
+	if backend, ok := agentContext["backend_db"]; ok {
+		prompt += "\n\nUse Cases (instrument each of these):\n" + backend
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" {
+			switch art.Language {
+			case "go":
+				artifacts[i].Filename = fmt.Sprintf("internal/infrastructure/observability/observability_%d.go", i+1)
+			case "json":
+				artifacts[i].Filename = "deploy/grafana/dashboard.json"
+			}
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
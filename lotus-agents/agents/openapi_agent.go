@@ -0,0 +1,89 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+const openAPIResponsibilities = `- Produce the machine-readable OpenAPI 3.1 contract for the service's HTTP interface
+- The contract is consumed by frontend teams and API gateways, so it must be complete and self-contained
+- Every endpoint must match a route registered in internal/interfaces/http/router/router.go exactly (method + path)
+- Every request/response body must reference a schema under components/schemas mirroring the HTTP DTOs
+- Every business operation from the service definition must be implemented by at least one endpoint`
+
+const openAPIOutputFormat = `Produce exactly one file:
+
+  api/openapi.yaml
+      → openapi: 3.1.0, info (title, version), servers, paths, components/schemas
+
+Rules for every operation:
+  - operationId: unique lowerCamelCase identifier
+  - x-operation: the EXACT text of the business operation it implements, copied from the service definition
+    (omit only for auxiliary endpoints such as list/get-by-id that implement no listed operation)
+  - declare every {path} parameter under parameters with in: path and required: true
+  - declare all responses, including the standard error envelope for 4xx/5xx
+  - use $ref: '#/components/schemas/<Name>' for request and response bodies
+
+Format: ` + "```yaml\n# file: api/openapi.yaml\n<document>\n```" + `
+
+Do not generate Go code.`
+
+// OpenAPIAgent emits the OpenAPI 3.1 contract for the service's REST API
+type OpenAPIAgent struct {
+	*BaseAgent
+}
+
+func NewOpenAPIAgent(cfg *config.Config, svc *config.ServiceDefinition) *OpenAPIAgent {
+	return &OpenAPIAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "OpenAPI Specification Agent", svc, openAPIResponsibilities, openAPIOutputFormat),
+	}
+}
+
+func (a *OpenAPIAgent) Description() string {
+	return "Writes the machine-readable OpenAPI 3.1 contract (api/openapi.yaml) for the REST API"
+}
+
+func (a *OpenAPIAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Write the OpenAPI 3.1 specification for the following microservice:
+
+%s
+
+Please produce:
+
+1. One path item per route, with the same HTTP method and path template as the router
+1. An x-operation extension on each endpoint naming the business operation it implements
+1. Component schemas for every request and response DTO, with required fields and formats
+1. Path, query and header parameters (including Idempotency-Key where applicable)
+1. Security schemes for the JWT bearer authentication used by the service
+1. The shared error response schema used for all 4xx/5xx responses`, svc.Prompt())
+
+	if api, ok := agentContext["api_design"]; ok {
+		prompt += "\n\nAPI Design (the spec must describe exactly these routes and DTOs):\n" + api
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	artifacts := ParseArtifacts(output)
+	for i, art := range artifacts {
+		if art.Filename == "" && (art.Language == "yaml" || art.Language == "yml") {
+			artifacts[i].Filename = "api/openapi.yaml"
+		}
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: artifacts,
+	}, nil
+}
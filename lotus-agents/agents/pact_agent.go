@@ -0,0 +1,112 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// ContractTestDir holds the generated Pact tests
+const ContractTestDir = "test/contract/"
+
+const pactResponsibilities = `- Write consumer-driven contract tests with pact-go v2 (github.com/pact-foundation/pact-go/v2)
+- Consumer tests: one per REST integration, driving the service's real HTTP client adapter against the Pact
+  mock server; cover every request the adapter makes, with matchers (Like, EachLike, Regex) instead of literals
+- Message pacts: one per consumed Kafka event, feeding the pact message into the real consumer handler
+  and asserting it is processed; the payload shape must match the event struct exactly
+- Provider verification: verify the service's own endpoints against pacts from a broker or the pacts/ directory,
+  with state handlers that seed data through the repositories, and publish verification results in CI only
+- Every contract test file starts with //go:build contract so go test ./... works without the Pact FFI
+- Pact files are written to pacts/; consumer name is the service name`
+
+const pactOutputFormat = `Produce these files (every code block MUST start with // file: <path>):
+
+  test/contract/consumer/<provider>_consumer_pact_test.go   (one per REST integration)
+  test/contract/message/<event>_message_pact_test.go         (one per consumed event)
+  test/contract/provider/provider_verification_test.go       (only if the service exposes REST)
+
+Format: ` + "```go\n// file: test/contract/<dir>/<name>_test.go\n//go:build contract\n\n<code>\n```"
+
+// PactAgent writes consumer, message and provider contract tests for any microservice
+type PactAgent struct {
+	*BaseAgent
+	artifacts []Artifact
+}
+
+func NewPactAgent(cfg *config.Config, svc *config.ServiceDefinition) *PactAgent {
+	return &PactAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Contract Test Agent", svc, pactResponsibilities, pactOutputFormat),
+	}
+}
+
+func (a *PactAgent) Description() string {
+	return "Writes Pact consumer tests for REST integrations, message pacts for consumed events and provider verification"
+}
+
+// UseArtifacts receives the generated tree so tests drive the real clients, consumers and handlers
+func (a *PactAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *PactAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	var providers []string
+	for _, in := range svc.IntegrationsOfKind(config.KindREST) {
+		providers = append(providers, fmt.Sprintf("%s (Pact provider name %q)", in.Name, in.Slug()))
+	}
+	restProviders := "none"
+	if len(providers) > 0 {
+		restProviders = strings.Join(providers, ", ")
+	}
+
+	prompt := fmt.Sprintf(`Write contract tests for the following microservice:
+
+%s
+
+Please produce:
+
+1. Consumer pact tests for these REST integrations: %s
+1. Message pact tests for every event the service consumes (see the event structs and consumers below)
+1. Provider verification for the service's own endpoints (consumer name %q): %t`,
+		svc.Prompt(), restProviders, svc.Name, svc.Exposes(config.InterfaceREST))
+
+	for _, art := range a.artifacts {
+		if isContractSurface(art.Filename) {
+			prompt += "\n\n" + codeBlock(art)
+		}
+	}
+	if messaging, ok := agentContext["messaging"]; ok {
+		prompt += "\n\nMessaging (from Messaging Agent):\n" + messaging
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: ParseArtifacts(output),
+	}, nil
+}
+
+// isContractSurface reports whether a file is part of a contract the tests
+// pin down: the API spec, event structs, Kafka consumers or outbound clients
+func isContractSurface(filename string) bool {
+	if !strings.HasSuffix(filename, ".go") && !strings.HasSuffix(filename, ".yaml") {
+		return false
+	}
+	if strings.HasSuffix(filename, "_test.go") {
+		return false
+	}
+	return strings.HasSuffix(filename, "api/openapi.yaml") ||
+		strings.Contains(filename, "internal/domain/event/") ||
+		strings.Contains(filename, "kafka/consumer/") ||
+		strings.Contains(filename, "client")
+}
@@ -0,0 +1,114 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// PlanPath is where the design plan is written in the generated tree
+const PlanPath = "docs/plan.json"
+
+// plannerRepairAttempts is how many times an invalid plan is sent back with its validation errors
+const plannerRepairAttempts = 2
+
+const plannerResponsibilities = `- Decide the names every other agent will use, before any code is written
+- One use case per business operation, named <Verb><Noun>UseCase (e.g. ReserveStockUseCase)
+- Ports: an input port per use case, output ports for external calls, a repository per aggregate
+- Tables in snake_case plural, one per persisted entity, plus outbox and processed_events when events are used
+- Events named <Noun><PastTenseVerb>Event with topic <service>.<event-name>.v<version> and direction send|receive
+- Endpoints with method, path, operationId and the use case they call (omit if the service has no REST API)
+- Produce names only; no code`
+
+const plannerOutputFormat = `Respond with a single JSON object in one code block and nothing else:
+
+` + "```json" + `
+{
+  "use_cases": [{"name": "ReserveStockUseCase", "operation": "<operation from the definition>"}],
+  "ports": [{"name": "ReserveStockInputPort", "kind": "input|output|repository", "methods": ["Execute"]}],
+  "tables": [{"name": "stock_items", "entity": "StockItem"}],
+  "events": [{"name": "StockReservedEvent", "topic": "inventory.stock-reserved.v1", "direction": "send|receive"}],
+  "endpoints": [{"method": "POST", "path": "/api/v1/reservations", "operation_id": "reserveStock", "use_case": "ReserveStockUseCase"}]
+}
+` + "```"
+
+// PlannerAgent produces the shared design plan for any microservice
+type PlannerAgent struct {
+	*BaseAgent
+	plan *spec.Plan
+}
+
+func NewPlannerAgent(cfg *config.Config, svc *config.ServiceDefinition) *PlannerAgent {
+	return &PlannerAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Planner Agent", svc, plannerResponsibilities, plannerOutputFormat),
+	}
+}
+
+func (a *PlannerAgent) Description() string {
+	return "Plans use case, port, table, event, topic and endpoint names shared by every later agent"
+}
+
+// Plan returns the validated plan once Run has succeeded
+func (a *PlannerAgent) Plan() *spec.Plan { return a.plan }
+
+func (a *PlannerAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	prompt := fmt.Sprintf(`Plan the names for the following microservice:
+
+%s
+
+Cover every operation, entity and integration listed above.`, svc.Prompt())
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
+	}
+
+	for attempt := 0; ; attempt++ {
+		output, err := a.Chat(ctx, messages)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+		}
+
+		plan, problems := parsePlanOutput(output)
+		if len(problems) == 0 {
+			a.plan = plan
+			data, err := json.MarshalIndent(plan, "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+			}
+			return &AgentResult{
+				AgentName: a.Name(),
+				Output:    output,
+				Artifacts: []Artifact{{Filename: PlanPath, Language: "json", Content: string(data) + "\n"}},
+			}, nil
+		}
+		if attempt == plannerRepairAttempts {
+			return nil, fmt.Errorf("[%s] failed: plan is invalid: %s", a.Name(), strings.Join(problems, "; "))
+		}
+
+		messages = append(messages,
+			anthropic.NewAssistantMessage(anthropic.NewTextBlock(output)),
+			anthropic.NewUserMessage(anthropic.NewTextBlock("The plan failed validation:\n- "+strings.Join(problems, "\n- ")+"\n\nReturn the complete corrected plan.")),
+		)
+	}
+}
+
+// parsePlanOutput extracts and validates the plan from the planner's response
+func parsePlanOutput(output string) (*spec.Plan, []string) {
+	for _, art := range ParseArtifacts(output) {
+		if art.Language != "json" {
+			continue
+		}
+		plan, err := spec.ParsePlan([]byte(art.Content))
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		return plan, plan.Validate()
+	}
+	return nil, []string{"no json code block in response"}
+}
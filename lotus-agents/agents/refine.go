@@ -0,0 +1,107 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Refiner is implemented by agents that can revise their own artifacts in
+// response to review findings. Every agent built on BaseAgent is a Refiner.
+type Refiner interface {
+	Refine(ctx context.Context, svc *config.ServiceDefinition, artifacts []Artifact, findings []ReviewFinding) ([]Artifact, error)
+}
+
+// Refine asks the agent to fix the given findings in the artifacts it
+// produced earlier and returns the complete files it changed
+func (b *BaseAgent) Refine(ctx context.Context, svc *config.ServiceDefinition, artifacts []Artifact, findings []ReviewFinding) ([]Artifact, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("A reviewer audited the files you generated for the %s microservice and reported these findings:\n\n", svc.Name))
+	for _, f := range findings {
+		sb.WriteString("- " + f.String() + "\n")
+	}
+	sb.WriteString("\nYour files as generated:\n\n")
+	for _, a := range artifacts {
+		if a.Filename == "" {
+			continue
+		}
+		sb.WriteString(codeBlock(a) + "\n\n")
+	}
+	sb.WriteString(`Fix every finding. Return the COMPLETE new content of each file you change, in the same
+format as before (every code block starts with its file comment). Do not return unchanged files,
+do not rename files, and do not change behaviour the findings do not call for.`)
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(sb.String())),
+	}
+	output, err := b.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] refine failed: %w", b.Name(), err)
+	}
+
+	var updated []Artifact
+	for _, a := range ParseArtifacts(output) {
+		if a.Filename != "" {
+			updated = append(updated, a)
+		}
+	}
+	return updated, nil
+}
+
+// MergeArtifacts replaces artifacts in base that share a filename with an
+// update and appends updates for new files, preserving the original order
+func MergeArtifacts(base, updates []Artifact) []Artifact {
+	byName := map[string]Artifact{}
+	for _, u := range updates {
+		byName[u.Filename] = u
+	}
+	merged := make([]Artifact, 0, len(base)+len(updates))
+	seen := map[string]bool{}
+	for _, a := range base {
+		if u, ok := byName[a.Filename]; ok && a.Filename != "" {
+			merged = append(merged, u)
+			seen[a.Filename] = true
+			continue
+		}
+		merged = append(merged, a)
+	}
+	for _, u := range updates {
+		if !seen[u.Filename] {
+			merged = append(merged, u)
+			seen[u.Filename] = true
+		}
+	}
+	return merged
+}
+
+// ensureFileHint returns the artifact content with its file comment as the first line
+func ensureFileHint(a Artifact) string {
+	first, _, _ := strings.Cut(a.Content, "\n")
+	if strings.Contains(first, "file:") {
+		return a.Content
+	}
+	prefix := "//"
+	switch a.Language {
+	case "markdown", "md":
+		return fmt.Sprintf("<!-- file: %s -->\n%s", a.Filename, a.Content)
+	case "sql":
+		prefix = "--"
+	case "yaml", "yml", "dockerfile", "docker", "makefile", "bash", "sh", "toml", "graphql", "gql":
+		prefix = "#"
+	}
+	return fmt.Sprintf("%s file: %s\n%s", prefix, a.Filename, a.Content)
+}
+
+// codeBlock fences the artifact with its file comment, using a fence longer
+// than any backtick run in the content so nested blocks survive
+func codeBlock(a Artifact) string {
+	fence := "```"
+	for strings.Contains(a.Content, fence) {
+		fence += "`"
+	}
+	return fence + a.Language + "\n" + ensureFileHint(a) + "\n" + fence
+}
@@ -0,0 +1,208 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// ReviewPath is where the review is written in the generated tree
+const ReviewPath = "REVIEW.md"
+
+// reviewMaxInputChars bounds the artifact text sent for review; files past
+// the budget are listed by name only
+const reviewMaxInputChars = 400_000
+
+// Review severities and categories
+const (
+	ReviewHigh   = "high"
+	ReviewMedium = "medium"
+	ReviewLow    = "low"
+
+	CategoryCorrectness = "correctness"
+	CategorySecurity    = "security"
+	CategoryConsistency = "consistency"
+	CategoryCAViolation = "ca-violation"
+)
+
+// ReviewFinding is one issue the Reviewer agent found in the combined output
+type ReviewFinding struct {
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+}
+
+func (f ReviewFinding) String() string {
+	loc := f.File
+	if f.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return fmt.Sprintf("[%s/%s] %s — %s", f.Severity, f.Category, loc, f.Message)
+}
+
+const reviewerResponsibilities = `- Audit the complete generated service as one codebase, not agent by agent
+- correctness: code that will not compile, wrong SQL, unhandled errors, broken transactions, races
+- security: missing authz on endpoints, injection, secrets in code or manifests, unsafe defaults
+- consistency: names, paths, DTO fields, topics, env vars or ports that disagree between files
+- ca-violation: imports that break the Dependency Rule or logic placed in the wrong layer
+- Report only concrete, verifiable issues with the exact file and line; no style nitpicks`
+
+const reviewerOutputFormat = `Respond with one JSON array in a single code block, nothing else:
+
+` + "```json" + `
+[
+  {"severity": "high|medium|low", "file": "<path>", "line": <line or 0>,
+   "category": "correctness|security|consistency|ca-violation", "message": "<issue and the fix>"}
+]
+` + "```" + `
+
+Use "high" only for issues that break the build, lose data, or expose the service.`
+
+// ReviewerAgent audits the combined output of every other agent
+type ReviewerAgent struct {
+	*BaseAgent
+	artifacts []Artifact
+}
+
+func NewReviewerAgent(cfg *config.Config, svc *config.ServiceDefinition) *ReviewerAgent {
+	return &ReviewerAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Reviewer Agent", svc, reviewerResponsibilities, reviewerOutputFormat),
+	}
+}
+
+func (a *ReviewerAgent) Description() string {
+	return "Audits all generated files together for correctness, security, consistency and Clean Architecture violations"
+}
+
+// UseArtifacts receives the full generated tree to review
+func (a *ReviewerAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *ReviewerAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Review the complete generated code for the following microservice:\n\n%s\nGenerated files:\n\n", svc.Prompt()))
+
+	budget := reviewMaxInputChars
+	var omitted []string
+	for _, art := range a.artifacts {
+		if art.Filename == "" || art.Filename == ReviewPath {
+			continue
+		}
+		if len(art.Content) > budget {
+			omitted = append(omitted, art.Filename)
+			continue
+		}
+		budget -= len(art.Content)
+		sb.WriteString(codeBlock(art) + "\n\n")
+	}
+	if len(omitted) > 0 {
+		sb.WriteString("Also generated but not shown (review budget exceeded): " + strings.Join(omitted, ", ") + "\n")
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(sb.String())),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	findings, err := parseReviewFindings(output)
+	if err != nil {
+		output += fmt.Sprintf("\n\n> Review findings could not be parsed: %v\n", err)
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: []Artifact{{Filename: ReviewPath, Language: "markdown", Content: ReviewMarkdown(svc.Name, findings, nil)}},
+		Findings:  findings,
+	}, nil
+}
+
+// parseReviewFindings reads the JSON findings array from the reviewer's response
+func parseReviewFindings(output string) ([]ReviewFinding, error) {
+	for _, art := range ParseArtifacts(output) {
+		if art.Language != "json" {
+			continue
+		}
+		var findings []ReviewFinding
+		if err := json.Unmarshal([]byte(art.Content), &findings); err != nil {
+			return nil, err
+		}
+		for i := range findings {
+			findings[i].Severity = strings.ToLower(strings.TrimSpace(findings[i].Severity))
+			findings[i].Category = strings.ToLower(strings.TrimSpace(findings[i].Category))
+		}
+		sort.SliceStable(findings, func(i, j int) bool {
+			return severityRank(findings[i].Severity) < severityRank(findings[j].Severity)
+		})
+		return findings, nil
+	}
+	return nil, fmt.Errorf("no json code block in response")
+}
+
+func severityRank(s string) int {
+	switch s {
+	case ReviewHigh:
+		return 0
+	case ReviewMedium:
+		return 1
+	}
+	return 2
+}
+
+// ReviewMarkdown renders the findings as REVIEW.md. refined maps each agent
+// that ran a Refine round to the files it rewrote.
+func ReviewMarkdown(service string, findings []ReviewFinding, refined map[string][]string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s — Review\n\n", service))
+
+	counts := map[string]int{}
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	sb.WriteString(fmt.Sprintf("%d finding(s): %d high, %d medium, %d low.\n\n",
+		len(findings), counts[ReviewHigh], counts[ReviewMedium], counts[ReviewLow]))
+
+	if len(findings) > 0 {
+		sb.WriteString("| Severity | Category | Location | Finding |\n|---|---|---|---|\n")
+		for _, f := range findings {
+			loc := f.File
+			if f.Line > 0 {
+				loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+			}
+			msg := strings.ReplaceAll(f.Message, "|", `\|`)
+			msg = strings.ReplaceAll(msg, "\n", " ")
+			sb.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s |\n", f.Severity, f.Category, loc, msg))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(refined) > 0 {
+		sb.WriteString("## Refinement\n\n")
+		names := make([]string, 0, len(refined))
+		for name := range refined {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			files := refined[name]
+			if len(files) == 0 {
+				sb.WriteString(fmt.Sprintf("- **%s** made no changes\n", name))
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("- **%s** rewrote %s\n", name, "`"+strings.Join(files, "`, `")+"`"))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -15,7 +15,7 @@ const testingResponsibilities = `- Write tests at every CA layer (unit tests moc
 - Place test files next to the code they test (e.g. internal/domain/entity/payment_test.go)
 - Use table-driven tests; mock repository interfaces with hand-written or mockery-generated mocks
 - Integration tests use testcontainers-go (PostgreSQL, Kafka as needed)
-- Makefile: test, test-integration, test-race, coverage, lint, build, run targets`
+- Makefile: test, test-integration, test-race, coverage, lint, build, run and seed targets`
 
 const testingOutputFormat = `Produce these files (every code block MUST start with // file: <path>):
 
@@ -36,7 +36,7 @@ const testingOutputFormat = `Produce these files (every code block MUST start wi
   Makefile
 
 Format Go: ` + "```go\n// file: <path>/<filename>.go\n<code>\n```" + `
-Format Makefile: ` + "```makefile\n// file: Makefile\n<content>\n```"
+Format Makefile: ` + "```makefile\n# file: Makefile\n<content>\n```"
 
 // TestingSecurityAgent writes tests and implements security for any microservice
 type TestingSecurityAgent struct {
@@ -74,7 +74,7 @@ Please produce:
 - Unauthorized access attempts
 - Input validation / injection attempts
 - Any domain-specific security concerns
-1. Makefile with: test, test-integration, coverage, lint targets`, svc.Prompt())
+1. Makefile with: test, test-integration, coverage, lint, seed targets`, svc.Prompt())
 
 	if api, ok := agentContext["api_design"]; ok {
 		prompt += "\n\nAPI Design (write tests and middleware for these endpoints):\n" + api
@@ -82,6 +82,22 @@ Please produce:
 	if backend, ok := agentContext["backend_db"]; ok {
 		prompt += "\n\nService/Repo Layer (mock these interfaces in tests):\n" + backend
 	}
+	if obs, ok := agentContext["observability"]; ok {
+		prompt += "\n\nObservability (main.go calls observability.Setup first, defers its shutdown, uses its slog logger,\n" +
+			"wraps every use case with the RED decorator and mounts the tracing middleware and /metrics):\n" + obs
+	}
+	if fixtures, ok := agentContext["fixtures"]; ok {
+		prompt += "\n\nSeed Data (tests may use these fixture builders):\n" + fixtures
+	}
+	if seed, ok := agentContext["seed_command"]; ok {
+		prompt += "\n\nMakefile seed target (declare a seed target that runs exactly this command):\n" + seed
+	}
+	if integrations, ok := agentContext["integrations"]; ok {
+		prompt += "\n\nIntegrations (main.go builds these adapters from their Config and injects them as the use cases' output ports):\n" + integrations
+	}
+	if caching, ok := agentContext["caching"]; ok {
+		prompt += "\n\nCaching (main.go wraps the PostgreSQL repositories in these Redis decorators and starts the invalidation handler):\n" + caching
+	}
 
 	messages := []anthropic.MessageParam{
 		anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
@@ -0,0 +1,123 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// ThreatModelPath is where the threat model is written in the generated tree
+const ThreatModelPath = "THREAT_MODEL.md"
+
+// threatModelMaxInputChars bounds the contract text (OpenAPI, event structs,
+// routes) sent to the agent; the remaining files are listed by name only
+const threatModelMaxInputChars = 80_000
+
+// STRIDE categories, in the order the report lists them
+var STRIDECategories = []string{
+	"Spoofing",
+	"Tampering",
+	"Repudiation",
+	"Information Disclosure",
+	"Denial of Service",
+	"Elevation of Privilege",
+}
+
+const threatModelResponsibilities = `- Model the threats specific to this service's domain, data and integrations, not generic web risks
+- Derive trust boundaries and data flows from the generated API, events and integrations
+- Apply STRIDE to every entry point: each REST/gRPC/GraphQL endpoint, each consumed topic, each outbound call
+- Call out regulatory scope implied by the definition (e.g. PCI-DSS for card data, consent/opt-out for messaging)
+- Map every mitigation to the generated file that implements it; if nothing implements it, it is an open risk
+- Never claim a mitigation exists without naming the file`
+
+const threatModelOutputFormat = `Respond with the complete THREAT_MODEL.md as plain Markdown (not wrapped in a code block) with these sections:
+
+1. "## Scope" — assets, actors, regulatory scope
+2. "## Data Flow" — a ` + "```mermaid" + ` flowchart with trust boundaries as subgraphs
+3. "## STRIDE" — one table with columns: ID | Category | Component | Threat | Mitigation | Implemented in
+   Category is one of Spoofing, Tampering, Repudiation, Information Disclosure, Denial of Service,
+   Elevation of Privilege, and every category appears at least once.
+   "Implemented in" lists generated file paths in backticks, or "—" when unmitigated.
+4. "## Open Risks" — every unmitigated or partially mitigated threat with its ID, impact and recommended fix`
+
+// ThreatModelAgent writes a STRIDE threat model for any microservice
+type ThreatModelAgent struct {
+	*BaseAgent
+	artifacts []Artifact
+}
+
+func NewThreatModelAgent(cfg *config.Config, svc *config.ServiceDefinition) *ThreatModelAgent {
+	return &ThreatModelAgent{
+		BaseAgent: NewBaseAgentForService(cfg, "Threat Model Agent", svc, threatModelResponsibilities, threatModelOutputFormat),
+	}
+}
+
+func (a *ThreatModelAgent) Description() string {
+	return "Writes a STRIDE threat model with a data-flow diagram, mitigations mapped to generated files and open risks"
+}
+
+// UseArtifacts receives the generated tree so mitigations can cite real files
+func (a *ThreatModelAgent) UseArtifacts(artifacts []Artifact) { a.artifacts = artifacts }
+
+func (a *ThreatModelAgent) Run(ctx context.Context, svc *config.ServiceDefinition, agentContext map[string]string) (*AgentResult, error) {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Write the threat model for the following microservice:\n\n%s\n", svc.Prompt()))
+
+	if security, ok := agentContext["testing_security"]; ok {
+		sb.WriteString("\nSecurity Controls (from Testing & Security Agent):\n" + security + "\n")
+	}
+
+	sb.WriteString("\nAPI and event contracts:\n\n")
+	budget := threatModelMaxInputChars
+	var files []string
+	for _, art := range a.artifacts {
+		if art.Filename == "" {
+			continue
+		}
+		files = append(files, art.Filename)
+		if !isThreatSurface(art.Filename) || len(art.Content) > budget {
+			continue
+		}
+		budget -= len(art.Content)
+		sb.WriteString(codeBlock(art) + "\n\n")
+	}
+	sb.WriteString("Generated files (cite these paths in \"Implemented in\"):\n")
+	for _, f := range files {
+		sb.WriteString("  - " + f + "\n")
+	}
+
+	messages := []anthropic.MessageParam{
+		anthropic.NewUserMessage(anthropic.NewTextBlock(sb.String())),
+	}
+
+	output, err := a.Chat(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("[%s] failed: %w", a.Name(), err)
+	}
+
+	return &AgentResult{
+		AgentName: a.Name(),
+		Output:    output,
+		Artifacts: []Artifact{{Filename: ThreatModelPath, Language: "markdown", Content: strings.TrimSpace(output) + "\n"}},
+	}, nil
+}
+
+// isThreatSurface reports whether a file defines an entry point or contract
+// the threat model must cover
+func isThreatSurface(filename string) bool {
+	switch {
+	case strings.HasSuffix(filename, "openapi.yaml"),
+		strings.HasSuffix(filename, ".proto"),
+		strings.HasSuffix(filename, ".graphql"),
+		strings.HasSuffix(filename, ".graphqls"),
+		strings.HasSuffix(filename, "router.go"),
+		strings.Contains(filename, "internal/domain/event/"),
+		strings.Contains(filename, "middleware"):
+		return true
+	}
+	return false
+}
@@ -22,6 +22,31 @@ type Config struct {
 	// MaxTokens is the maximum number of tokens per response, read from CLAUDE_MAX_TOKENS.
 	// Defaults to 8192.
 	MaxTokens int
+
+	// RefineRounds is how many times high-severity review findings are sent
+	// back to the owning agents for a fix, read from PIPELINE_REFINE_ROUNDS.
+	// Defaults to 0 (review only).
+	RefineRounds int
+
+	// CheckFeedback sends the error findings of a stage's deterministic check
+	// (e.g. migration analysis after the Backend agent) back to that agent once
+	// for a fix, read from PIPELINE_CHECK_FEEDBACK. Defaults to false.
+	CheckFeedback bool
+
+	// ExecuteMigrations applies the generated migrations to a scratch
+	// database in the sandbox selected by Sandbox, read from
+	// PIPELINE_EXECUTE_MIGRATIONS. Defaults to false.
+	ExecuteMigrations bool
+
+	// RunTests runs the generated test suite and the race detector in a
+	// scratch copy of the tree, read from PIPELINE_RUN_TESTS. Defaults to false.
+	RunTests bool
+
+	// Sandbox selects how the generated tests and migrations are isolated:
+	// "bwrap", "docker" or "process", read from PIPELINE_SANDBOX. Empty uses
+	// bubblewrap and fails when it does not work on this host; "process" has
+	// no isolation.
+	Sandbox string
 }
 
 // Load reads configuration from environment variables and returns a populated Config.
@@ -38,9 +63,25 @@ func Load() *Config {
 		}
 	}
 
+	refineRounds := 0
+	if v := os.Getenv("PIPELINE_REFINE_ROUNDS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			refineRounds = n
+		}
+	}
+
+	checkFeedback, _ := strconv.ParseBool(os.Getenv("PIPELINE_CHECK_FEEDBACK"))
+	executeMigrations, _ := strconv.ParseBool(os.Getenv("PIPELINE_EXECUTE_MIGRATIONS"))
+	runTests, _ := strconv.ParseBool(os.Getenv("PIPELINE_RUN_TESTS"))
+
 	return &Config{
-		AnthropicAPIKey: os.Getenv("ANTHROPIC_API_KEY"),
-		Model:           model,
-		MaxTokens:       maxTokens,
+		AnthropicAPIKey:   os.Getenv("ANTHROPIC_API_KEY"),
+		Model:             model,
+		MaxTokens:         maxTokens,
+		RefineRounds:      refineRounds,
+		CheckFeedback:     checkFeedback,
+		ExecuteMigrations: executeMigrations,
+		RunTests:          runTests,
+		Sandbox:           os.Getenv("PIPELINE_SANDBOX"),
 	}
 }
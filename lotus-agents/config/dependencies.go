@@ -0,0 +1,157 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// majorVersionRe matches a major version subdirectory, which is a module of its own
+var majorVersionRe = regexp.MustCompile(`^v[0-9]+(/|$)`)
+
+// Dependency is a third-party Go module generated services may import
+type Dependency struct {
+	Path    string
+	Version string
+	Purpose string
+}
+
+// ApprovedDependencies are the only third-party modules generated Go code may
+// import, pinned to the version written to go.mod
+var ApprovedDependencies = []Dependency{
+	{"github.com/go-chi/chi/v5", "v5.2.1", "HTTP routing and middleware"},
+	{"github.com/jackc/pgx/v5", "v5.7.2", "PostgreSQL driver and pool (pgxpool)"},
+	{"github.com/golang-migrate/migrate/v4", "v4.18.1", "running SQL migrations"},
+	{"github.com/redis/go-redis/v9", "v9.7.0", "Redis client"},
+	{"github.com/twmb/franz-go", "v1.18.0", "Kafka producer and consumer (pkg/kgo)"},
+	{"github.com/google/uuid", "v1.6.0", "UUIDs"},
+	{"github.com/sony/gobreaker", "v1.0.0", "circuit breaker for outbound calls"},
+	{"golang.org/x/sync", "v0.10.0", "errgroup and singleflight"},
+	{"github.com/prometheus/client_golang", "v1.20.5", "Prometheus metrics"},
+	{"go.opentelemetry.io/otel", "v1.33.0", "OpenTelemetry API and propagators"},
+	{"go.opentelemetry.io/otel/sdk", "v1.33.0", "OpenTelemetry tracer provider"},
+	{"go.opentelemetry.io/otel/trace", "v1.33.0", "OpenTelemetry tracing API"},
+	{"go.opentelemetry.io/otel/metric", "v1.33.0", "OpenTelemetry metrics API"},
+	{"go.opentelemetry.io/otel/sdk/metric", "v1.33.0", "OpenTelemetry meter provider"},
+	{"go.opentelemetry.io/otel/exporters/prometheus", "v0.55.0", "exposing OpenTelemetry metrics to Prometheus"},
+	{"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc", "v1.33.0", "OTLP trace exporter"},
+	{"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp", "v0.58.0", "HTTP server and client tracing"},
+	{"google.golang.org/grpc", "v1.69.2", "gRPC server and client"},
+	{"google.golang.org/protobuf", "v1.36.1", "protobuf runtime"},
+	{"github.com/99designs/gqlgen", "v0.17.61", "GraphQL server"},
+	{"github.com/vektah/gqlparser/v2", "v2.5.21", "GraphQL schema types used by gqlgen"},
+	{"github.com/stretchr/testify", "v1.10.0", "test assertions and mocks"},
+	{"github.com/testcontainers/testcontainers-go", "v0.34.0", "integration test containers"},
+	{"github.com/testcontainers/testcontainers-go/modules/postgres", "v0.34.0", "PostgreSQL test container"},
+	{"github.com/testcontainers/testcontainers-go/modules/redis", "v0.34.0", "Redis test container"},
+	{"github.com/testcontainers/testcontainers-go/modules/kafka", "v0.34.0", "Kafka test container"},
+	{"github.com/pact-foundation/pact-go/v2", "v2.0.8", "consumer-driven contract tests"},
+}
+
+// nestedModules are the directories of approved modules, at the pinned
+// versions, that are separate modules; packages under them are not provided
+// by the approved module and need their own allow-list entry
+var nestedModules = map[string][]string{
+	"go.opentelemetry.io/otel": {
+		"bridge", "example", "exporters", "internal/tools", "log", "metric", "schema", "sdk", "trace",
+	},
+	"go.opentelemetry.io/otel/sdk":                                  {"log", "metric"},
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp": {"example", "test"},
+	"google.golang.org/grpc": {
+		"cmd/protoc-gen-go-grpc", "examples", "gcp/observability", "interop/observability",
+		"security/advancedtls", "stats/opencensus", "test/tools",
+	},
+	"github.com/twmb/franz-go": {
+		"examples", "pkg/kadm", "pkg/kfake", "pkg/kmsg", "pkg/sasl/kerberos", "pkg/sr", "plugin",
+	},
+	"github.com/redis/go-redis/v9":                {"example", "extra"},
+	"github.com/prometheus/client_golang":         {"exp"},
+	"github.com/testcontainers/testcontainers-go": {"examples", "modules"},
+	"github.com/99designs/gqlgen":                 {"_examples"},
+}
+
+// dependencyRewrites map module paths to the approved module with the same
+// API; imports under the old path are rewritten
+var dependencyRewrites = map[string]string{
+	"github.com/go-chi/chi":        "github.com/go-chi/chi/v5",
+	"github.com/go-redis/redis/v8": "github.com/redis/go-redis/v9",
+	"github.com/go-redis/redis/v9": "github.com/redis/go-redis/v9",
+}
+
+// dependencyAlternatives name what to use instead of common unapproved modules
+var dependencyAlternatives = map[string]string{
+	"github.com/gorilla/mux":         "github.com/go-chi/chi/v5",
+	"github.com/gin-gonic/gin":       "github.com/go-chi/chi/v5",
+	"github.com/labstack/echo/v4":    "github.com/go-chi/chi/v5",
+	"github.com/gofiber/fiber/v2":    "github.com/go-chi/chi/v5",
+	"github.com/jackc/pgx/v4":        "github.com/jackc/pgx/v5",
+	"github.com/lib/pq":              "github.com/jackc/pgx/v5",
+	"github.com/jmoiron/sqlx":        "github.com/jackc/pgx/v5",
+	"gorm.io/gorm":                   "github.com/jackc/pgx/v5",
+	"github.com/Shopify/sarama":      "github.com/twmb/franz-go",
+	"github.com/IBM/sarama":          "github.com/twmb/franz-go",
+	"github.com/segmentio/kafka-go":  "github.com/twmb/franz-go",
+	"github.com/go-redis/redis":      "github.com/redis/go-redis/v9",
+	"github.com/sirupsen/logrus":     "log/slog",
+	"go.uber.org/zap":                "log/slog",
+	"github.com/rs/zerolog":          "log/slog",
+	"github.com/pkg/errors":          "errors",
+	"github.com/satori/go.uuid":      "github.com/google/uuid",
+	"github.com/gofrs/uuid":          "github.com/google/uuid",
+	"github.com/golang/protobuf":     "google.golang.org/protobuf",
+	"github.com/sony/gobreaker/v2":   "github.com/sony/gobreaker",
+	"github.com/golang/mock":         "github.com/stretchr/testify",
+	"go.uber.org/mock":               "github.com/stretchr/testify",
+	"github.com/DATA-DOG/go-sqlmock": "github.com/testcontainers/testcontainers-go/modules/postgres",
+}
+
+// ApprovedDependency returns the approved module providing an import path.
+// Packages in a nested module or another major version of an approved
+// module are not provided by it and are not approved.
+func ApprovedDependency(importPath string) (Dependency, bool) {
+	var best Dependency
+	for _, d := range ApprovedDependencies {
+		if underModule(importPath, d.Path) && len(d.Path) > len(best.Path) {
+			best = d
+		}
+	}
+	if best.Path == "" {
+		return Dependency{}, false
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(importPath, best.Path), "/")
+	if majorVersionRe.MatchString(rel) {
+		return Dependency{}, false
+	}
+	for _, dir := range nestedModules[best.Path] {
+		if underModule(rel, dir) {
+			return Dependency{}, false
+		}
+	}
+	return best, true
+}
+
+// DependencyRewrite returns the approved import path that replaces an import
+// of a module with the same API under another path, or ""
+func DependencyRewrite(importPath string) string {
+	for old, approved := range dependencyRewrites {
+		if underModule(importPath, old) && !underModule(importPath, approved) {
+			return approved + strings.TrimPrefix(importPath, old)
+		}
+	}
+	return ""
+}
+
+// DependencyAlternative returns what to use instead of an unapproved import, or ""
+func DependencyAlternative(importPath string) string {
+	best := ""
+	for mod := range dependencyAlternatives {
+		if underModule(importPath, mod) && len(mod) > len(best) {
+			best = mod
+		}
+	}
+	return dependencyAlternatives[best]
+}
+
+// underModule reports whether importPath is module or one of its packages
+func underModule(importPath, module string) bool {
+	return importPath == module || strings.HasPrefix(importPath, module+"/")
+}
@@ -0,0 +1,227 @@
+package config
+
+import "strings"
+
+// Integration kinds recognised in ServiceDefinition.Integrations
+const (
+	KindPostgres      = "postgres"
+	KindMySQL         = "mysql"
+	KindMongoDB       = "mongodb"
+	KindRedis         = "redis"
+	KindKafka         = "kafka"
+	KindRabbitMQ      = "rabbitmq"
+	KindElasticsearch = "elasticsearch"
+	KindREST          = "rest"
+	KindGRPC          = "grpc"
+	KindExternal      = "external"
+)
+
+// integrationKeywords maps lower-case keywords to kinds, most specific
+// first. Keywords match as word prefixes unless whole is set.
+var integrationKeywords = []struct {
+	keyword string
+	kind    string
+	whole   bool
+}{
+	{"postgres", KindPostgres, false},
+	{"mysql", KindMySQL, false},
+	{"mariadb", KindMySQL, false},
+	{"mongo", KindMongoDB, false},
+	{"redis", KindRedis, false},
+	{"kafka", KindKafka, false},
+	{"rabbitmq", KindRabbitMQ, false},
+	{"elasticsearch", KindElasticsearch, false},
+	{"opensearch", KindElasticsearch, false},
+	{"grpc", KindGRPC, false},
+	{"rest", KindREST, true},
+	{"http", KindREST, true},
+}
+
+// Integration is a parsed ServiceDefinition.Integrations entry,
+// e.g. "Redis (stock level cache)" → {Name: "Redis", Purpose: "stock level cache", Kind: "redis"}
+type Integration struct {
+	Raw     string
+	Name    string
+	Purpose string
+	Kind    string
+}
+
+// ParseIntegration classifies a free-text integration entry by keyword.
+// Entries naming no known technology are KindREST when the name ends in
+// "Service", i.e. another service of the platform, and otherwise KindExternal,
+// e.g. a vendor API such as "Stripe API".
+func ParseIntegration(raw string) Integration {
+	in := Integration{Raw: raw, Name: strings.TrimSpace(raw), Kind: KindExternal}
+	if open := strings.Index(raw, "("); open >= 0 {
+		in.Name = strings.TrimSpace(raw[:open])
+		in.Purpose = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(raw[open+1:]), ")"))
+	}
+	lower := strings.ToLower(raw)
+	for _, k := range integrationKeywords {
+		match := containsWord
+		if k.whole {
+			match = containsWholeWord
+		}
+		if match(lower, k.keyword) {
+			in.Kind = k.kind
+			return in
+		}
+	}
+	if strings.HasSuffix(strings.ToLower(in.Name), " service") {
+		in.Kind = KindREST
+	}
+	return in
+}
+
+// containsWord reports whether keyword occurs in s as a whole word or word prefix
+func containsWord(s, keyword string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		j += i
+		if j == 0 || !isAlnum(s[j-1]) {
+			return true
+		}
+		i = j + 1
+	}
+}
+
+// containsWholeWord reports whether keyword occurs in s as a whole word
+func containsWholeWord(s, keyword string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		j += i
+		end := j + len(keyword)
+		if (j == 0 || !isAlnum(s[j-1])) && (end == len(s) || !isAlnum(s[end])) {
+			return true
+		}
+		i = j + 1
+	}
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9'
+}
+
+// IsInfrastructure reports whether the integration is a datastore or broker
+// the service runs alongside, as opposed to another service or vendor API
+func (i Integration) IsInfrastructure() bool {
+	switch i.Kind {
+	case KindPostgres, KindMySQL, KindMongoDB, KindRedis, KindKafka, KindRabbitMQ, KindElasticsearch:
+		return true
+	}
+	return false
+}
+
+// DefaultPort returns the port the integration's server listens on by
+// default, or 0 when the kind has no conventional port
+func (i Integration) DefaultPort() int {
+	switch i.Kind {
+	case KindPostgres:
+		return 5432
+	case KindMySQL:
+		return 3306
+	case KindMongoDB:
+		return 27017
+	case KindRedis:
+		return 6379
+	case KindKafka:
+		return 9092
+	case KindRabbitMQ:
+		return 5672
+	case KindElasticsearch:
+		return 9200
+	}
+	return 0
+}
+
+// ParsedIntegrations returns every integration entry classified by kind
+func (s *ServiceDefinition) ParsedIntegrations() []Integration {
+	out := make([]Integration, 0, len(s.Integrations))
+	for _, raw := range s.Integrations {
+		out = append(out, ParseIntegration(raw))
+	}
+	return out
+}
+
+// InfrastructureKinds returns the distinct datastore/broker kinds the
+// service depends on, in declaration order
+func (s *ServiceDefinition) InfrastructureKinds() []string {
+	var kinds []string
+	seen := map[string]bool{}
+	for _, in := range s.ParsedIntegrations() {
+		if in.IsInfrastructure() && !seen[in.Kind] {
+			seen[in.Kind] = true
+			kinds = append(kinds, in.Kind)
+		}
+	}
+	return kinds
+}
+
+// HasIntegration reports whether any integration is of the given kind
+func (s *ServiceDefinition) HasIntegration(kind string) bool {
+	for _, in := range s.ParsedIntegrations() {
+		if in.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IntegrationsOfKind returns the integrations of the given kind in declaration order
+func (s *ServiceDefinition) IntegrationsOfKind(kind string) []Integration {
+	var out []Integration
+	for _, in := range s.ParsedIntegrations() {
+		if in.Kind == kind {
+			out = append(out, in)
+		}
+	}
+	return out
+}
+
+// Slug returns the integration name in lower kebab case, e.g. "Order Service" → "order-service".
+// Contract tests use it as the Pact participant name.
+func (i Integration) Slug() string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(i.Name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return sb.String()
+}
+
+// IsExternalAPI reports whether the integration is another service or vendor
+// API the service calls over the network through an outbound client
+func (i Integration) IsExternalAPI() bool {
+	return i.Kind == KindREST || i.Kind == KindExternal
+}
+
+// Package returns the Go package name for the integration's client adapter,
+// e.g. "Stripe API" → "stripeapi"
+func (i Integration) Package() string {
+	return strings.ReplaceAll(i.Slug(), "-", "")
+}
+
+// ExternalAPIs returns the vendor and service APIs the service calls, in declaration order
+func (s *ServiceDefinition) ExternalAPIs() []Integration {
+	var out []Integration
+	for _, in := range s.ParsedIntegrations() {
+		if in.IsExternalAPI() {
+			out = append(out, in)
+		}
+	}
+	return out
+}
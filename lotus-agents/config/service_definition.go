@@ -1,6 +1,9 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ServiceDefinition describes the microservice to be built.
 // This is the single place where you define what you want built —
@@ -15,6 +18,10 @@ type ServiceDefinition struct {
 	// Language is the programming language to use, e.g. "Go", "Python", "Node.js"
 	Language string
 
+	// ModulePath is the Go module path of the generated service, e.g.
+	// "github.com/acme/payments". Empty means github.com/example/<Name>.
+	ModulePath string
+
 	// Entities are the core domain objects, e.g. ["Product", "StockItem", "Warehouse"]
 	Entities []string
 
@@ -27,6 +34,77 @@ type ServiceDefinition struct {
 
 	// ExtraRequirements are any freeform additional requirements
 	ExtraRequirements []string
+
+	// Interfaces are the API styles the service exposes, e.g. ["rest", "grpc", "graphql"].
+	// Empty means REST only.
+	Interfaces []string
+
+	// OpenAPISpec is an optional, already agreed OpenAPI document (YAML or JSON).
+	// When set the pipeline runs in contract-first mode: the document is the
+	// authoritative API and agents must implement it rather than invent one.
+	OpenAPISpec string
+
+	// CIProvider selects the CI system the pipeline writes definitions for,
+	// "github" (GitHub Actions) or "gitlab" (GitLab CI). Empty means GitHub Actions.
+	CIProvider string
+
+	// Plan is the shared design plan (JSON) produced by the Planner agent.
+	// The pipeline sets it once the planner has run; every later agent sees it
+	// in Prompt() as the single source of truth for names.
+	Plan string
+}
+
+// API interface styles accepted in ServiceDefinition.Interfaces
+const (
+	InterfaceREST    = "rest"
+	InterfaceGRPC    = "grpc"
+	InterfaceGraphQL = "graphql"
+)
+
+// CI providers accepted in ServiceDefinition.CIProvider
+const (
+	CIGitHubActions = "github"
+	CIGitLab        = "gitlab"
+)
+
+// CI returns the CI provider to generate definitions for
+func (s *ServiceDefinition) CI() string {
+	if strings.EqualFold(s.CIProvider, CIGitLab) {
+		return CIGitLab
+	}
+	return CIGitHubActions
+}
+
+// Exposes reports whether the service exposes the given interface style
+func (s *ServiceDefinition) Exposes(style string) bool {
+	if len(s.Interfaces) == 0 {
+		return style == InterfaceREST
+	}
+	for _, i := range s.Interfaces {
+		if strings.EqualFold(i, style) {
+			return true
+		}
+	}
+	return false
+}
+
+// Module returns the Go module path the generated go.mod declares
+func (s *ServiceDefinition) Module() string {
+	if s.ModulePath != "" {
+		return s.ModulePath
+	}
+	return "github.com/example/" + strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s.Name), " ", "-"))
+}
+
+// IsGo reports whether the service is written in Go
+func (s *ServiceDefinition) IsGo() bool {
+	lang := strings.ToLower(strings.TrimSpace(s.Language))
+	return lang == "go" || lang == "golang" || strings.HasPrefix(lang, "go ")
+}
+
+// ContractFirst reports whether the service is generated from an existing OpenAPI document
+func (s *ServiceDefinition) ContractFirst() bool {
+	return s.OpenAPISpec != ""
 }
 
 // Prompt builds a structured prompt string from the service definition,
@@ -36,6 +114,19 @@ func (s *ServiceDefinition) Prompt() string {
 	p += fmt.Sprintf("Description:\n%s\n\n", s.Description)
 	p += fmt.Sprintf("Language: %s\n\n", s.Language)
 
+	if s.IsGo() {
+		p += fmt.Sprintf("Go Module Path: %s (import the service's own packages under this path)\n\n", s.Module())
+		p += "Approved Third-Party Go Modules (import nothing else outside the standard library; go.mod is generated from these; nested modules such as go.opentelemetry.io/otel/sdk/metric are not covered by their parent):\n"
+		for _, d := range ApprovedDependencies {
+			p += fmt.Sprintf("  - %s — %s\n", d.Path, d.Purpose)
+		}
+		p += "\n"
+	}
+
+	if len(s.Interfaces) > 0 {
+		p += fmt.Sprintf("API Interfaces: %s\n\n", strings.Join(s.Interfaces, ", "))
+	}
+
 	if len(s.Entities) > 0 {
 		p += "Core Domain Entities:\n"
 		for _, e := range s.Entities {
@@ -68,6 +159,24 @@ func (s *ServiceDefinition) Prompt() string {
 		p += "\n"
 	}
 
+	if s.Plan != "" {
+		p += "Shared Design Plan (single source of naming truth — use exactly these use case, port, table, event, topic and endpoint names; do not invent alternatives or additional ones):\n"
+		p += s.Plan
+		if !strings.HasSuffix(s.Plan, "\n") {
+			p += "\n"
+		}
+		p += "\n"
+	}
+
+	if s.ContractFirst() {
+		p += "Authoritative API Contract (OpenAPI — implement exactly these paths, parameters and schemas; do not invent endpoints or fields):\n"
+		p += s.OpenAPISpec
+		if !strings.HasSuffix(s.OpenAPISpec, "\n") {
+			p += "\n"
+		}
+		p += "\n"
+	}
+
 	return p
 
 }
@@ -0,0 +1,231 @@
+// Package gotest runs the generated service's Go tests in a sandboxed scratch
+// copy of the tree and summarises `go test -json` output per package.
+package gotest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Package outcomes
+const (
+	StatusPass      = "pass"
+	StatusFail      = "fail"
+	StatusSkip      = "skip"
+	StatusBuildFail = "build failed"
+)
+
+// maxFailureOutput bounds the output kept per failing test
+const maxFailureOutput = 4000
+
+var coverageRe = regexp.MustCompile(`coverage: ([\d.]+)% of statements`)
+
+// event is one line of `go test -json` output (cmd/test2json)
+type event struct {
+	Time        time.Time
+	Action      string
+	Package     string
+	ImportPath  string
+	Test        string
+	Elapsed     float64
+	Output      string
+	FailedBuild string
+}
+
+// Summary is the result of one `go test` invocation
+type Summary struct {
+	// Name identifies the run, e.g. "go test -race ./..."
+	Name     string
+	Packages []*PackageResult
+
+	// Sandbox names the sandbox.Runner the tests ran under
+	Sandbox string
+
+	// Output holds build errors and anything else printed outside a package's tests
+	Output string
+
+	// Err is set when the run could not start or finish
+	Err error
+}
+
+// PackageResult is the outcome of one package's tests
+type PackageResult struct {
+	Package  string
+	Status   string
+	Passed   int
+	Failed   int
+	Skipped  int
+	Elapsed  time.Duration
+	Coverage float64 // percent of statements, or -1 when not reported
+	Failures []TestFailure
+
+	// BuildOutput holds the compiler errors when the package or its tests did not build
+	BuildOutput string
+}
+
+// TestFailure is a failing test and the output it printed
+type TestFailure struct {
+	Test   string
+	Output string
+}
+
+// Passed reports whether every package built and passed
+func (s *Summary) Passed() bool {
+	if s.Err != nil {
+		return false
+	}
+	for _, p := range s.Packages {
+		if p.Status == StatusFail || p.Status == StatusBuildFail {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse reads `go test -json` output; lines that are not JSON events go to Summary.Output
+func Parse(r io.Reader) *Summary {
+	s := &Summary{}
+	byPkg := map[string]*PackageResult{}
+	pkg := func(name string) *PackageResult {
+		p, ok := byPkg[name]
+		if !ok {
+			p = &PackageResult{Package: name, Coverage: -1}
+			byPkg[name] = p
+		}
+		return p
+	}
+	testOutput := map[string]*strings.Builder{}
+	buildOutput := map[string]*strings.Builder{}
+	var other strings.Builder
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 8*1024*1024)
+	for sc.Scan() {
+		line := sc.Bytes()
+		var ev event
+		if len(line) == 0 || line[0] != '{' || json.Unmarshal(line, &ev) != nil {
+			other.Write(line)
+			other.WriteByte('\n')
+			continue
+		}
+		switch ev.Action {
+		case "build-output":
+			b, ok := buildOutput[ev.ImportPath]
+			if !ok {
+				b = &strings.Builder{}
+				buildOutput[ev.ImportPath] = b
+			}
+			b.WriteString(ev.Output)
+			continue
+		case "build-fail":
+			continue
+		}
+		if ev.Package == "" {
+			continue
+		}
+		p := pkg(ev.Package)
+		key := ev.Package + " " + ev.Test
+		switch ev.Action {
+		case "output":
+			if m := coverageRe.FindStringSubmatch(ev.Output); m != nil && ev.Test == "" {
+				p.Coverage, _ = strconv.ParseFloat(m[1], 64)
+			}
+			if ev.Test != "" {
+				b, ok := testOutput[key]
+				if !ok {
+					b = &strings.Builder{}
+					testOutput[key] = b
+				}
+				b.WriteString(ev.Output)
+			}
+		case "pass", "fail", "skip":
+			if ev.Test == "" {
+				p.Status = ev.Action
+				p.Elapsed = time.Duration(ev.Elapsed * float64(time.Second))
+				if ev.FailedBuild != "" {
+					p.Status = StatusBuildFail
+					if b, ok := buildOutput[ev.FailedBuild]; ok {
+						p.BuildOutput = b.String()
+					}
+				}
+				continue
+			}
+			switch ev.Action {
+			case "pass":
+				p.Passed++
+			case "skip":
+				p.Skipped++
+			case "fail":
+				p.Failed++
+				out := ""
+				if b, ok := testOutput[key]; ok {
+					out = b.String()
+				}
+				if len(out) > maxFailureOutput {
+					out = "...\n" + out[len(out)-maxFailureOutput:]
+				}
+				p.Failures = append(p.Failures, TestFailure{Test: ev.Test, Output: out})
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		s.Err = fmt.Errorf("read go test output: %w", err)
+	}
+
+	for _, p := range byPkg {
+		if p.Status == "" {
+			p.Status = StatusFail // the run ended before the package reported
+		}
+		s.Packages = append(s.Packages, p)
+	}
+	sort.Slice(s.Packages, func(i, j int) bool { return s.Packages[i].Package < s.Packages[j].Package })
+	s.Output = other.String()
+	return s
+}
+
+// Markdown renders the summary as a markdown section
+func (s *Summary) Markdown() string {
+	var sb strings.Builder
+	status := "✅ passed"
+	if !s.Passed() {
+		status = "❌ failed"
+	}
+	sb.WriteString(fmt.Sprintf("## `%s` — %s\n\n", s.Name, status))
+	if s.Sandbox != "" {
+		sb.WriteString(fmt.Sprintf("Sandbox: %s\n\n", s.Sandbox))
+	}
+	if s.Err != nil {
+		sb.WriteString(fmt.Sprintf("The run did not complete: %v\n\n", s.Err))
+	}
+	if len(s.Packages) > 0 {
+		sb.WriteString("| Package | Result | Passed | Failed | Skipped | Coverage | Time |\n|---|---|---|---|---|---|---|\n")
+		for _, p := range s.Packages {
+			cov := "—"
+			if p.Coverage >= 0 {
+				cov = fmt.Sprintf("%.1f%%", p.Coverage)
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %d | %d | %d | %s | %s |\n",
+				p.Package, p.Status, p.Passed, p.Failed, p.Skipped, cov, p.Elapsed.Round(10*time.Millisecond)))
+		}
+		sb.WriteString("\n")
+	}
+	for _, p := range s.Packages {
+		if p.BuildOutput != "" {
+			sb.WriteString(fmt.Sprintf("### `%s` build errors\n\n```\n%s```\n\n", p.Package, p.BuildOutput))
+		}
+		for _, f := range p.Failures {
+			sb.WriteString(fmt.Sprintf("### `%s` %s\n\n```\n%s```\n\n", p.Package, f.Test, f.Output))
+		}
+	}
+	if out := strings.TrimSpace(s.Output); out != "" {
+		sb.WriteString(fmt.Sprintf("### Other output\n\n```\n%s\n```\n\n", out))
+	}
+	return sb.String()
+}
@@ -0,0 +1,203 @@
+package gotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/sandbox"
+)
+
+// invocations are the go test runs: the suite with coverage, then the race detector
+var invocations = [][]string{
+	{"test", "-json", "-cover", "-timeout", "5m", "./..."},
+	{"test", "-json", "-race", "-timeout", "5m", "./..."},
+}
+
+// inheritedEnv are the variables passed through to go test; everything else is dropped
+var inheritedEnv = []string{"PATH", "CC", "CGO_ENABLED"}
+
+// Run writes the artifacts to a temp dir and runs the test suite and the race
+// detector there through runner, with the tree read-only and go.mod edits
+// kept in a scratch copy. Modules resolve from the local module cache only
+// and proxy variables point at a closed port, so even the process runner
+// cannot fetch anything.
+func Run(ctx context.Context, runner sandbox.Runner, artifacts []agents.Artifact) ([]*Summary, error) {
+	dir, err := os.MkdirTemp("", "lotus-gotest-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	src, scratch := filepath.Join(dir, "src"), filepath.Join(dir, "scratch")
+	for _, d := range []string{src, filepath.Join(scratch, "tmp")} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			return nil, err
+		}
+	}
+	if err := WriteTree(src, artifacts); err != nil {
+		return nil, err
+	}
+	modfile, err := copyModFiles(src, scratch)
+	if err != nil {
+		return nil, err
+	}
+	if err := setReadOnly(src, true); err != nil {
+		return nil, err
+	}
+	defer setReadOnly(src, false)
+
+	goroot, modcache, err := goEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cmd := sandbox.Command{
+		Dir:      src,
+		ReadOnly: []string{goroot, modcache},
+		Writable: []string{scratch},
+		Limits:   sandbox.DefaultLimits,
+	}
+	// A fresh build cache per run, so one generated tree cannot plant build
+	// output that a later run would trust
+	gocache := filepath.Join(scratch, "gocache")
+	if err := os.MkdirAll(gocache, 0755); err != nil {
+		return nil, err
+	}
+	cmd.Env = sandboxEnv(scratch, gocache, modcache)
+
+	var summaries []*Summary
+	for _, args := range invocations {
+		cmd.Args = append([]string{"go"}, args...)
+		if modfile != "" {
+			cmd.Args = append([]string{"go", args[0], "-modfile=" + modfile}, args[1:]...)
+		}
+		s := run(ctx, runner, cmd)
+		s.Name = "go " + strings.Replace(strings.Join(args, " "), "-json ", "", 1)
+		summaries = append(summaries, s)
+	}
+	return summaries, nil
+}
+
+// WriteTree writes every named artifact to its path under dir
+func WriteTree(dir string, artifacts []agents.Artifact) error {
+	for _, a := range artifacts {
+		if a.Filename == "" {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(a.Filename))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("artifact %s escapes the tree", a.Filename)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(a.Content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func run(ctx context.Context, runner sandbox.Runner, cmd sandbox.Command) *Summary {
+	var stdout, stderr bytes.Buffer
+	err := runner.Run(ctx, cmd, &stdout, &stderr)
+
+	s := Parse(&stdout)
+	s.Sandbox = runner.Name()
+	s.Output += stderr.String()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(err, sandbox.ErrTimeout):
+		s.Err = err
+	case err != nil && !errors.As(err, &exitErr):
+		s.Err = err
+	case err != nil && len(s.Packages) == 0:
+		s.Err = fmt.Errorf("go test exited with %v before running any package", err)
+	}
+	return s
+}
+
+// copyModFiles copies go.mod and go.sum into scratch so go test can update
+// them through -modfile while the tree stays read-only. It returns the
+// scratch go.mod, or "" when the tree has none.
+func copyModFiles(src, scratch string) (string, error) {
+	mod, err := os.ReadFile(filepath.Join(src, "go.mod"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	modfile := filepath.Join(scratch, "go.mod")
+	if err := os.WriteFile(modfile, mod, 0644); err != nil {
+		return "", err
+	}
+	// -modfile=x/go.mod reads and writes its checksums in x/go.sum
+	sum, err := os.ReadFile(filepath.Join(src, "go.sum"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	return modfile, os.WriteFile(filepath.Join(scratch, "go.sum"), sum, 0644)
+}
+
+// setReadOnly removes or restores write permission throughout the tree; the
+// process runner has no read-only mount to rely on
+func setReadOnly(dir string, readOnly bool) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		mode := fs.FileMode(0644)
+		if d.IsDir() {
+			mode = 0755
+		}
+		if readOnly {
+			mode &^= 0222
+		}
+		return os.Chmod(path, mode)
+	})
+}
+
+// goEnv returns the host toolchain's GOROOT and module cache, which the sandbox mounts read-only
+func goEnv(ctx context.Context) (goroot, modcache string, err error) {
+	out, err := exec.CommandContext(ctx, "go", "env", "GOROOT", "GOMODCACHE").Output()
+	if err != nil {
+		return "", "", fmt.Errorf("go env: %w", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) != 2 {
+		return "", "", fmt.Errorf("go env: unexpected output %q", out)
+	}
+	return lines[0], lines[1], nil
+}
+
+// sandboxEnv is a minimal environment with the module proxy, checksum
+// database and HTTP proxies disabled and every writable path under scratch
+// or the build cache
+func sandboxEnv(scratch, gocache, modcache string) []string {
+	env := []string{
+		"GOPROXY=off",
+		"GOSUMDB=off",
+		"GOFLAGS=-mod=mod",
+		"GOTOOLCHAIN=local",
+		"GOCACHE=" + gocache,
+		"GOMODCACHE=" + modcache,
+		"HOME=" + scratch,
+		"TMPDIR=" + filepath.Join(scratch, "tmp"),
+		"HTTP_PROXY=http://127.0.0.1:9",
+		"HTTPS_PROXY=http://127.0.0.1:9",
+		"NO_PROXY=",
+	}
+	for _, k := range inheritedEnv {
+		if v, ok := os.LookupEnv(k); ok {
+			env = append(env, k+"="+v)
+		}
+	}
+	return env
+}
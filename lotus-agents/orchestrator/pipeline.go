@@ -2,53 +2,164 @@ package orchestrator
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
 	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/gotest"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/postprocess"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/sandbox"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/verify"
 )
 
 // Pipeline coordinates all agents to build a microservice
 type Pipeline struct {
-	agentFactories []func(svc *config.ServiceDefinition) agents.Agent
-	cfg            *config.Config
+	stages []stage
+	cfg    *config.Config
+
+	// migrationRuns caches migration execution reports by the migrations they ran
+	mu            sync.Mutex
+	migrationRuns map[string]*verify.Report
+}
+
+// stage registers one agent in the pipeline
+type stage struct {
+	// key is the context key downstream agents read this agent's output from
+	key string
+
+	// factory builds the agent with a system prompt tailored to the service
+	factory func(svc *config.ServiceDefinition) agents.Agent
+
+	// enabled decides whether the stage runs for a service; nil means always
+	enabled func(svc *config.ServiceDefinition) bool
+
+	// check verifies the agent's own artifacts as soon as it finishes; with
+	// cfg.CheckFeedback its errors are sent back to the agent once. nil means none.
+	check func(ctx context.Context, artifacts []agents.Artifact) []*verify.Report
 }
 
 // PipelineResult holds all outputs from a full pipeline run
 type PipelineResult struct {
 	Service   *config.ServiceDefinition
 	Results   []*agents.AgentResult
+	Reports   []*verify.Report
 	StartTime time.Time
 	EndTime   time.Time
 	Duration  time.Duration
+
+	// Tests holds the generated test suite's results when cfg.RunTests is set
+	Tests []*gotest.Summary
 }
 
 // NewPipeline creates a reusable pipeline wired with all agents.
 // Agents are constructed lazily per service so their system prompts
 // are tailored to the service being built.
 func NewPipeline(cfg *config.Config) *Pipeline {
-	return &Pipeline{
-		cfg: cfg,
-		agentFactories: []func(svc *config.ServiceDefinition) agents.Agent{
-			func(svc *config.ServiceDefinition) agents.Agent { return agents.NewAPIDesignAgent(cfg, svc) },
-			func(svc *config.ServiceDefinition) agents.Agent { return agents.NewBackendDBAgent(cfg, svc) },
-			func(svc *config.ServiceDefinition) agents.Agent { return agents.NewMessagingAgent(cfg, svc) },
-			func(svc *config.ServiceDefinition) agents.Agent { return agents.NewTestingSecurityAgent(cfg, svc) },
+	// Stage checks that cache per pipeline refer back to p
+	var p *Pipeline
+	p = &Pipeline{
+		cfg:           cfg,
+		migrationRuns: map[string]*verify.Report{},
+		stages: []stage{
+			{key: "plan", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewPlannerAgent(cfg, svc) }},
+			{
+				key:     "api_design",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewAPIDesignAgent(cfg, svc) },
+				enabled: exposesREST,
+			},
+			{
+				key:     "openapi",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewOpenAPIAgent(cfg, svc) },
+				enabled: func(svc *config.ServiceDefinition) bool { return exposesREST(svc) && !svc.ContractFirst() },
+			},
+			{
+				key:     "openapi",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewContractAgent() },
+				enabled: func(svc *config.ServiceDefinition) bool { return exposesREST(svc) && svc.ContractFirst() },
+			},
+			{
+				key:     "integrations",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewIntegrationsAgent(cfg, svc) },
+				enabled: func(svc *config.ServiceDefinition) bool { return len(svc.ExternalAPIs()) > 0 },
+			},
+			{
+				key:     "backend_db",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewBackendDBAgent(cfg, svc) },
+				check: func(ctx context.Context, artifacts []agents.Artifact) []*verify.Report {
+					reports := []*verify.Report{verify.CheckMigrations(artifacts)}
+					if cfg.ExecuteMigrations {
+						reports = append(reports, p.executeMigrations(ctx, artifacts))
+					}
+					return reports
+				},
+			},
+			{key: "observability", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewObservabilityAgent(cfg, svc) }},
+			{
+				key:     "grpc",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewGRPCAgent(cfg, svc) },
+				enabled: func(svc *config.ServiceDefinition) bool { return svc.Exposes(config.InterfaceGRPC) },
+			},
+			{
+				key:     "graphql",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewGraphQLAgent(cfg, svc) },
+				enabled: func(svc *config.ServiceDefinition) bool { return svc.Exposes(config.InterfaceGraphQL) },
+			},
+			{key: "messaging", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewMessagingAgent(cfg, svc) }},
+			{
+				key:     "caching",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewCachingAgent(cfg, svc) },
+				enabled: func(svc *config.ServiceDefinition) bool { return svc.HasIntegration(config.KindRedis) },
+			},
+			{key: "fixtures", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewFixturesAgent(cfg, svc) }},
+			{key: "testing_security", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewTestingSecurityAgent(cfg, svc) }},
+			{
+				key:     "load_test",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewLoadTestAgent(cfg, svc) },
+				enabled: exposesREST,
+			},
+			{
+				key:     "contract_tests",
+				factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewPactAgent(cfg, svc) },
+				enabled: needsContractTests,
+			},
+			{key: "deployment", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewDeploymentAgent(cfg, svc) }},
+			{key: "kubernetes", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewKubernetesAgent(cfg, svc) }},
+			{key: "ci", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewCIAgent(cfg, svc) }},
+			{key: "threat_model", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewThreatModelAgent(cfg, svc) }},
+			{key: "docs", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewDocsAgent(cfg, svc) }},
+			{key: "review", factory: func(svc *config.ServiceDefinition) agents.Agent { return agents.NewReviewerAgent(cfg, svc) }},
 		},
 	}
+	return p
 }
 
-// contextKeys maps agent index → the key used to pass its output to downstream agents
-var contextKeys = []string{"api_design", "backend_db", "messaging", "testing_security"}
+func exposesREST(svc *config.ServiceDefinition) bool { return svc.Exposes(config.InterfaceREST) }
+
+// needsContractTests reports whether the service has a REST or Kafka boundary to pin down with Pact
+func needsContractTests(svc *config.ServiceDefinition) bool {
+	return exposesREST(svc) || svc.HasIntegration(config.KindREST) || svc.HasIntegration(config.KindKafka)
+}
 
 // Run executes all agents for the given service definition, chaining outputs as context
 func (p *Pipeline) Run(ctx context.Context, svc *config.ServiceDefinition) (*PipelineResult, error) {
 	result := &PipelineResult{Service: svc, StartTime: time.Now()}
 
+	// Fail before any agent runs rather than after, or fall back to no isolation
+	if p.cfg.RunTests {
+		if _, err := sandbox.New(p.cfg.Sandbox); err != nil {
+			return nil, fmt.Errorf("run tests: %w (see PIPELINE_SANDBOX)", err)
+		}
+	}
+
 	// Seed context with the full service definition prompt
 	agentContext := map[string]string{
 		"project_context": svc.Prompt(),
@@ -58,9 +169,16 @@ func (p *Pipeline) Run(ctx context.Context, svc *config.ServiceDefinition) (*Pip
 	fmt.Printf("║  Building: %-38s║\n", svc.Name+" microservice")
 	fmt.Printf("╚══════════════════════════════════════════════════╝\n\n")
 
-	agentList := make([]agents.Agent, len(p.agentFactories))
-	for i, factory := range p.agentFactories {
-		agentList[i] = factory(svc)
+	var agentList []agents.Agent
+	var keys []string
+	var checks []func(context.Context, []agents.Artifact) []*verify.Report
+	for _, st := range p.stages {
+		if st.enabled != nil && !st.enabled(svc) {
+			continue
+		}
+		agentList = append(agentList, st.factory(svc))
+		keys = append(keys, st.key)
+		checks = append(checks, st.check)
 	}
 
 	for i, agent := range agentList {
@@ -74,30 +192,495 @@ func (p *Pipeline) Run(ctx context.Context, svc *config.ServiceDefinition) (*Pip
 			fmt.Printf("  %s\n\n", desc)
 		}
 
+		if c, ok := agent.(agents.ArtifactConsumer); ok {
+			c.UseArtifacts(result.Artifacts())
+		}
+
 		agentResult, err := agent.Run(ctx, svc, agentContext)
 		if err != nil {
 			return nil, fmt.Errorf("agent %q failed: %w", agent.Name(), err)
 		}
+		if err := p.feedback(ctx, svc, agent, checks[i], agentResult); err != nil {
+			return nil, err
+		}
 
 		// Pass a trimmed summary to downstream agents
-		if i < len(contextKeys) {
-			summary := agentResult.Output
-			if len(summary) > 3000 {
-				summary = summary[:3000] + "\n... [truncated]"
-			}
-			agentContext[contextKeys[i]] = summary
+		summary := agentResult.Output
+		if len(summary) > 3000 {
+			summary = summary[:3000] + "\n... [truncated]"
+		}
+		agentContext[keys[i]] = summary
+		// The seed command ends the fixtures output, past the truncation
+		if fixtures, ok := agent.(*agents.FixturesAgent); ok && fixtures.SeedCommand() != "" {
+			agentContext["seed_command"] = fixtures.SeedCommand()
 		}
 
 		result.Results = append(result.Results, agentResult)
+
+		// The plan reaches every later agent verbatim through svc.Prompt()
+		if planner, ok := agent.(*agents.PlannerAgent); ok && planner.Plan() != nil {
+			planned := *svc
+			planned.Plan = agentResult.Artifacts[0].Content
+			svc = &planned
+			result.Service = svc
+			agentContext["project_context"] = svc.Prompt()
+		}
 		fmt.Printf("  ✓ Complete — %d artifact(s) generated\n\n", len(agentResult.Artifacts))
 	}
 
+	if err := p.refine(ctx, svc, result, agentList); err != nil {
+		return nil, err
+	}
+
+	p.runGenerators(svc, result)
+	generateGoModule(svc, result)
+	formatArtifacts(result)
+	tests, err := p.runTests(ctx, svc, result, agentList)
+	if err != nil {
+		return nil, err
+	}
+	result.Tests = tests
+	result.Reports = p.runChecks(ctx, svc, result)
+
 	result.EndTime = time.Now()
 	result.Duration = result.EndTime.Sub(result.StartTime)
 	return result, nil
 
 }
 
+// refine sends high-severity review findings back to the agent that owns
+// each file, merges the rewritten files into that agent's result and
+// re-runs the reviewer, for up to cfg.RefineRounds rounds. agentList[i]
+// produced result.Results[i].
+func (p *Pipeline) refine(ctx context.Context, svc *config.ServiceDefinition, result *PipelineResult, agentList []agents.Agent) error {
+	reviewIdx := -1
+	for i, agent := range agentList {
+		if _, ok := agent.(*agents.ReviewerAgent); ok {
+			reviewIdx = i
+		}
+	}
+	if reviewIdx < 0 || p.cfg.RefineRounds == 0 {
+		return nil
+	}
+
+	refined := map[string][]string{}
+	for round := 1; round <= p.cfg.RefineRounds; round++ {
+		owners := map[string]int{}
+		for i, r := range result.Results {
+			for _, a := range r.Artifacts {
+				owners[a.Filename] = i
+			}
+		}
+		byOwner := map[int][]agents.ReviewFinding{}
+		for _, f := range result.Results[reviewIdx].Findings {
+			if idx, ok := owners[f.File]; ok && f.Severity == agents.ReviewHigh && idx != reviewIdx {
+				byOwner[idx] = append(byOwner[idx], f)
+			}
+		}
+		if len(byOwner) == 0 {
+			break
+		}
+
+		for idx := range result.Results {
+			findings := byOwner[idx]
+			refiner, ok := agentList[idx].(agents.Refiner)
+			if len(findings) == 0 || !ok {
+				continue
+			}
+			fmt.Printf("↻ Refine round %d: %s (%d finding(s))\n", round, agentList[idx].Name(), len(findings))
+			updated, err := refiner.Refine(ctx, svc, result.Results[idx].Artifacts, findings)
+			if err != nil {
+				return fmt.Errorf("agent %q failed: %w", agentList[idx].Name(), err)
+			}
+			result.Results[idx].Artifacts = agents.MergeArtifacts(result.Results[idx].Artifacts, updated)
+			name := agentList[idx].Name()
+			if _, ok := refined[name]; !ok {
+				refined[name] = []string{}
+			}
+			for _, u := range updated {
+				if !contains(refined[name], u.Filename) {
+					refined[name] = append(refined[name], u.Filename)
+				}
+			}
+			fmt.Printf("  ✓ %d file(s) rewritten\n\n", len(updated))
+		}
+
+		reviewer := agentList[reviewIdx]
+		if c, ok := reviewer.(agents.ArtifactConsumer); ok {
+			c.UseArtifacts(result.Artifacts())
+		}
+		review, err := reviewer.Run(ctx, svc, nil)
+		if err != nil {
+			return fmt.Errorf("agent %q failed: %w", reviewer.Name(), err)
+		}
+		result.Results[reviewIdx] = review
+	}
+
+	review := result.Results[reviewIdx]
+	for i, a := range review.Artifacts {
+		if a.Filename == agents.ReviewPath {
+			review.Artifacts[i].Content = agents.ReviewMarkdown(svc.Name, review.Findings, refined)
+		}
+	}
+	return nil
+}
+
+// feedback runs a stage's check over the artifacts its agent just produced
+// and, when cfg.CheckFeedback is set, sends the errors back to the agent once
+// and merges the files it rewrites
+func (p *Pipeline) feedback(ctx context.Context, svc *config.ServiceDefinition, agent agents.Agent, check func(context.Context, []agents.Artifact) []*verify.Report, res *agents.AgentResult) error {
+	refiner, ok := agent.(agents.Refiner)
+	if check == nil || !p.cfg.CheckFeedback || !ok {
+		return nil
+	}
+	var findings []agents.ReviewFinding
+	for _, report := range check(ctx, res.Artifacts) {
+		for _, f := range report.Findings {
+			if f.Severity == verify.SeverityError {
+				findings = append(findings, agents.ReviewFinding{
+					Severity: agents.ReviewHigh,
+					File:     f.File,
+					Line:     f.Line,
+					Category: report.Check,
+					Message:  f.Message,
+				})
+			}
+		}
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	fmt.Printf("  ↻ Checks: %d error(s) sent back to %s\n", len(findings), agent.Name())
+	updated, err := refiner.Refine(ctx, svc, res.Artifacts, findings)
+	if err != nil {
+		return fmt.Errorf("agent %q failed: %w", agent.Name(), err)
+	}
+	res.Artifacts = agents.MergeArtifacts(res.Artifacts, updated)
+	fmt.Printf("  ✓ %d file(s) rewritten\n", len(updated))
+	return nil
+}
+
+// runTests runs the generated test suite when cfg.RunTests is set. With
+// cfg.CheckFeedback, failing tests and test packages that do not build are
+// sent back to the Testing & Security agent once and the suite runs again.
+// agentList[i] produced result.Results[i].
+func (p *Pipeline) runTests(ctx context.Context, svc *config.ServiceDefinition, result *PipelineResult, agentList []agents.Agent) ([]*gotest.Summary, error) {
+	if !p.cfg.RunTests {
+		return nil, nil
+	}
+	runner, err := sandbox.New(p.cfg.Sandbox)
+	if err != nil {
+		return nil, fmt.Errorf("run tests: %w", err)
+	}
+	fmt.Printf("▶ Running the generated tests (%s sandbox)\n", runner.Name())
+	summaries, err := gotest.Run(ctx, runner, result.Artifacts())
+	if err != nil {
+		return nil, fmt.Errorf("run tests: %w", err)
+	}
+	printTestSummaries(summaries)
+
+	testerIdx := -1
+	for i, agent := range agentList {
+		if _, ok := agent.(*agents.TestingSecurityAgent); ok {
+			testerIdx = i
+		}
+	}
+	if !p.cfg.CheckFeedback || testerIdx < 0 {
+		return summaries, nil
+	}
+	refiner, ok := agentList[testerIdx].(agents.Refiner)
+	findings := testFindings(summaries, result.Artifacts(), result.Results[testerIdx].Artifacts)
+	if !ok || len(findings) == 0 {
+		return summaries, nil
+	}
+
+	fmt.Printf("  ↻ %d test failure(s) sent back to %s\n", len(findings), agentList[testerIdx].Name())
+	updated, err := refiner.Refine(ctx, svc, result.Results[testerIdx].Artifacts, findings)
+	if err != nil {
+		return nil, fmt.Errorf("agent %q failed: %w", agentList[testerIdx].Name(), err)
+	}
+	result.Results[testerIdx].Artifacts = agents.MergeArtifacts(result.Results[testerIdx].Artifacts, updated)
+	fmt.Printf("  ✓ %d file(s) rewritten\n", len(updated))
+	generateGoModule(svc, result)
+	formatArtifacts(result)
+
+	summaries, err = gotest.Run(ctx, runner, result.Artifacts())
+	if err != nil {
+		return nil, fmt.Errorf("run tests: %w", err)
+	}
+	printTestSummaries(summaries)
+	return summaries, nil
+}
+
+func printTestSummaries(summaries []*gotest.Summary) {
+	for _, s := range summaries {
+		status := "✓"
+		if !s.Passed() {
+			status = "✗"
+		}
+		failed := 0
+		for _, pkg := range s.Packages {
+			if pkg.Status == gotest.StatusFail || pkg.Status == gotest.StatusBuildFail {
+				failed++
+			}
+		}
+		fmt.Printf("  %s %-30s %d package(s), %d failed\n", status, s.Name, len(s.Packages), failed)
+	}
+	fmt.Println()
+}
+
+// testFindings turns failing tests and test packages that do not build into
+// findings against the tester's test files in that package's directory
+func testFindings(summaries []*gotest.Summary, all, owned []agents.Artifact) []agents.ReviewFinding {
+	module := ""
+	for _, a := range all {
+		if a.Filename == "go.mod" {
+			module = modulePath(a.Content)
+		}
+	}
+	var findings []agents.ReviewFinding
+	seen := map[string]bool{}
+	for _, s := range summaries {
+		for _, pkg := range s.Packages {
+			dir := strings.TrimPrefix(strings.TrimPrefix(pkg.Package, module), "/")
+			file := ""
+			for _, a := range owned {
+				if strings.HasSuffix(a.Filename, "_test.go") && path.Dir(a.Filename) == dir {
+					file = a.Filename
+					break
+				}
+			}
+			if file == "" {
+				continue
+			}
+			add := func(key, message string) {
+				if !seen[key] {
+					seen[key] = true
+					findings = append(findings, agents.ReviewFinding{Severity: agents.ReviewHigh, File: file, Category: "tests", Message: message})
+				}
+			}
+			if pkg.Status == gotest.StatusBuildFail {
+				add(pkg.Package, fmt.Sprintf("package %s does not build with its tests (`%s`):\n%s", pkg.Package, s.Name, pkg.BuildOutput))
+			}
+			for _, f := range pkg.Failures {
+				add(pkg.Package+" "+f.Test, fmt.Sprintf("%s fails (`%s`):\n%s", f.Test, s.Name, f.Output))
+			}
+		}
+	}
+	return findings
+}
+
+// modulePath returns the module path a go.mod file declares
+func modulePath(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		if f := strings.Fields(line); len(f) == 2 && f[0] == "module" {
+			return strings.Trim(f[1], `"`)
+		}
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// runGenerators derives additional artifacts deterministically from the
+// agents' output and records them as a pipeline result of their own
+func (p *Pipeline) runGenerators(svc *config.ServiceDefinition, result *PipelineResult) {
+	catalog, err := postprocess.AsyncAPI(svc, result.Artifacts())
+	switch {
+	case err != nil:
+		fmt.Printf("  ⚠ AsyncAPI catalog skipped: %v\n\n", err)
+	case catalog != nil:
+		result.Results = append(result.Results, &agents.AgentResult{
+			AgentName: "AsyncAPI Catalog",
+			Output:    fmt.Sprintf("Derived `%s` from the event structs in `%s`.", catalog.Filename, postprocess.EventDir),
+			Artifacts: []agents.Artifact{*catalog},
+		})
+		fmt.Printf("  ✓ Generated %s\n\n", catalog.Filename)
+	}
+
+	if !exposesREST(svc) {
+		return
+	}
+	clients, err := postprocess.Clients(svc, result.Artifacts())
+	switch {
+	case err != nil:
+		fmt.Printf("  ⚠ Client SDKs skipped: %v\n\n", err)
+	case clients != nil:
+		result.Results = append(result.Results, &agents.AgentResult{
+			AgentName: "Client SDKs",
+			Output:    fmt.Sprintf("Derived the Go client in `%s` and the TypeScript client in `%s` from `%s`.", postprocess.GoClientDir, postprocess.TSClientDir, verify.OpenAPIPath),
+			Artifacts: clients,
+		})
+		fmt.Printf("  ✓ Generated %s and %s\n\n", postprocess.GoClientDir, postprocess.TSClientDir)
+	}
+}
+
+// goModuleResult names the pipeline result holding the generated go.mod
+const goModuleResult = "Go Module"
+
+// generateGoModule replaces any go.mod or go.sum an agent wrote with one
+// derived by postprocess.GoModule, applying its import rewrites to the
+// agents' files, and records it as a pipeline result of its own. Running it
+// again regenerates that result.
+func generateGoModule(svc *config.ServiceDefinition, result *PipelineResult) {
+	var modResult *agents.AgentResult
+	for _, res := range result.Results {
+		if res.AgentName == goModuleResult {
+			modResult = res
+			res.Artifacts = nil
+			continue
+		}
+		kept := res.Artifacts[:0]
+		for _, a := range res.Artifacts {
+			if a.Filename == postprocess.GoModPath || a.Filename == "go.sum" {
+				fmt.Printf("  ⚠ Dropped %s written by %s\n", a.Filename, res.AgentName)
+				continue
+			}
+			kept = append(kept, a)
+		}
+		res.Artifacts = kept
+	}
+
+	rewritten, gomod := postprocess.GoModule(svc, result.Artifacts())
+	if gomod == nil {
+		return
+	}
+	changed, i := 0, 0
+	for _, res := range result.Results {
+		for j := range res.Artifacts {
+			if res.Artifacts[j].Content != rewritten[i].Content {
+				changed++
+			}
+			res.Artifacts[j] = rewritten[i]
+			i++
+		}
+	}
+	if modResult == nil {
+		modResult = &agents.AgentResult{AgentName: goModuleResult}
+		result.Results = append(result.Results, modResult)
+	}
+	modResult.Output = fmt.Sprintf("Derived `%s` for module `%s` from the imports of the generated code; rewrote imports in %d file(s).", gomod.Filename, svc.Module(), changed)
+	modResult.Artifacts = []agents.Artifact{*gomod}
+	fmt.Printf("  ✓ Generated %s (%d file(s) with rewritten imports)\n\n", gomod.Filename, changed)
+}
+
+// formatArtifacts runs every agent's Go, SQL, YAML and JSON files through
+// postprocess.Format so checks, tests and the saved tree see clean files
+func formatArtifacts(result *PipelineResult) {
+	formatted, problems := postprocess.Format(result.Artifacts())
+	changed, i := 0, 0
+	for _, res := range result.Results {
+		for j := range res.Artifacts {
+			if res.Artifacts[j].Content != formatted[i].Content {
+				changed++
+			}
+			res.Artifacts[j] = formatted[i]
+			i++
+		}
+	}
+	fmt.Printf("  ✓ Formatted %d file(s)", changed)
+	if len(problems) > 0 {
+		fmt.Printf(", %d problem(s) — see the Formatting check", len(problems))
+	}
+	fmt.Printf("\n\n")
+}
+
+// executeMigrations runs verify.ExecuteMigrations once per distinct set of
+// migrations, so the Backend & DB stage check and the final checks do not
+// start two databases for the same files. Migrations are keyed after
+// formatting, which runs between the two.
+func (p *Pipeline) executeMigrations(ctx context.Context, artifacts []agents.Artifact) *verify.Report {
+	h := sha256.New()
+	for _, a := range artifacts {
+		if strings.Contains(a.Filename, agents.MigrationDir) && strings.HasSuffix(a.Filename, ".sql") {
+			fmt.Fprintf(h, "%s\x00%s\x00", a.Filename, spec.FormatSQL(a.Content))
+		}
+	}
+	key := hex.EncodeToString(h.Sum(nil))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if report, ok := p.migrationRuns[key]; ok {
+		return report
+	}
+	report := verify.ExecuteMigrations(ctx, p.cfg.Sandbox, artifacts)
+	p.migrationRuns[key] = report
+	return report
+}
+
+// runChecks runs the deterministic checks over the combined artifacts of all agents
+func (p *Pipeline) runChecks(ctx context.Context, svc *config.ServiceDefinition, result *PipelineResult) []*verify.Report {
+	artifacts := result.Artifacts()
+	reports := []*verify.Report{
+		verify.CheckFormatting(artifacts),
+		verify.CheckDependencies(svc, artifacts),
+		verify.CheckPlanConformance(svc, artifacts),
+		verify.CheckMigrations(artifacts),
+		verify.CheckEventStructs(artifacts),
+		verify.CheckObservability(artifacts),
+		verify.CheckSeedData(artifacts),
+		verify.LintDockerfile(artifacts),
+		verify.CheckComposeStack(svc, artifacts),
+		verify.CheckKubernetesManifests(svc, artifacts),
+		verify.CheckCIPipeline(svc, artifacts),
+		verify.CheckThreatModel(artifacts),
+		verify.CheckDocumentation(svc, artifacts),
+	}
+	if p.cfg.ExecuteMigrations {
+		reports = append(reports, p.executeMigrations(ctx, artifacts))
+	}
+	if needsContractTests(svc) {
+		reports = append(reports, verify.CheckContractTests(svc, artifacts))
+	}
+	if exposesREST(svc) {
+		reports = append(reports, verify.CheckOpenAPI(svc, artifacts), verify.CheckLoadTests(artifacts))
+		if svc.ContractFirst() {
+			reports = append(reports, verify.CheckDTOConformance(artifacts))
+		}
+	}
+	if len(svc.ExternalAPIs()) > 0 {
+		reports = append(reports, verify.CheckIntegrations(svc, artifacts))
+	}
+	if svc.HasIntegration(config.KindRedis) {
+		reports = append(reports, verify.CheckCaching(artifacts))
+	}
+	if svc.Exposes(config.InterfaceGRPC) {
+		reports = append(reports, verify.LintProtos(artifacts))
+	}
+	if svc.Exposes(config.InterfaceGraphQL) {
+		reports = append(reports, verify.CheckGraphQLSchema(artifacts))
+	}
+	for _, r := range reports {
+		status := "✓"
+		if !r.Passed() {
+			status = "✗"
+		}
+		fmt.Printf("  %s %-30s %d error(s), %d warning(s)\n", status, r.Check, r.Count(verify.SeverityError), r.Count(verify.SeverityWarning))
+	}
+	fmt.Println()
+	return reports
+}
+
+// Artifacts returns the artifacts of every agent in pipeline order
+func (r *PipelineResult) Artifacts() []agents.Artifact {
+	var all []agents.Artifact
+	for _, res := range r.Results {
+		all = append(all, res.Artifacts...)
+	}
+	return all
+}
+
+// GeneratedFilesPath receives the generated file list when the
+// Documentation agent wrote its own README.md
+const GeneratedFilesPath = "docs/generated-files.md"
+
 // SaveArtifacts writes all generated files to outputDir/<service-name>/
 func SaveArtifacts(result *PipelineResult, outputDir string) error {
 	serviceDir := filepath.Join(outputDir, result.Service.Name)
@@ -143,7 +726,42 @@ func SaveArtifacts(result *PipelineResult, outputDir string) error {
 		summary.WriteString("\n")
 	}
 
-	return os.WriteFile(filepath.Join(serviceDir, "README.md"), []byte(summary.String()), 0644)
+	if len(result.Tests) > 0 {
+		tests := &strings.Builder{}
+		tests.WriteString(fmt.Sprintf("# %s — Test Results\n\n", result.Service.Name))
+		for _, s := range result.Tests {
+			tests.WriteString(s.Markdown())
+		}
+		if err := os.WriteFile(filepath.Join(serviceDir, "TEST_RESULTS.md"), []byte(tests.String()), 0644); err != nil {
+			return err
+		}
+		summary.WriteString("See `TEST_RESULTS.md` for the results of running the generated tests.\n")
+	}
+
+	if len(result.Reports) > 0 {
+		verification := &strings.Builder{}
+		verification.WriteString(fmt.Sprintf("# %s — Verification Report\n\n", result.Service.Name))
+		for _, r := range result.Reports {
+			verification.WriteString(r.Markdown())
+		}
+		if err := os.WriteFile(filepath.Join(serviceDir, "VERIFICATION.md"), []byte(verification.String()), 0644); err != nil {
+			return err
+		}
+		summary.WriteString("See `VERIFICATION.md` for the results of the deterministic checks.\n")
+	}
+
+	// The Documentation agent's README takes precedence over the file list
+	summaryPath := "README.md"
+	for _, a := range result.Artifacts() {
+		if a.Filename == agents.DocsReadmePath {
+			summaryPath = GeneratedFilesPath
+			break
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(serviceDir, summaryPath)), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(serviceDir, summaryPath), []byte(summary.String()), 0644)
 
 }
 
@@ -163,6 +781,12 @@ func extensionForLang(lang string) string {
 		ext = ".yaml"
 	case "json":
 		ext = ".json"
+	case "proto", "protobuf":
+		ext = ".proto"
+	case "graphql", "gql":
+		ext = ".graphqls"
+	case "dockerfile", "docker":
+		ext = ".dockerfile"
 	case "java":
 		ext = ".java"
 	case "springboot":
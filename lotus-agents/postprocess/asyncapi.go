@@ -0,0 +1,110 @@
+// Package postprocess holds deterministic generators that derive additional
+// artifacts from what the agents produced. Like the verify package it never
+// calls the Claude API.
+package postprocess
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// AsyncAPIPath is where the generated event catalog is written
+const AsyncAPIPath = "api/asyncapi.yaml"
+
+// EventDir is the Clean Architecture layer holding domain event structs
+const EventDir = "internal/domain/event/"
+
+// consumerDirs hold code that reacts to consumed events; an event struct
+// referenced there without a direction directive is treated as consumed
+var consumerDirs = []string{"internal/infrastructure/kafka/consumer/", "internal/application/usecase/handle_"}
+
+// ParseEventArtifacts parses every struct under internal/domain/event
+func ParseEventArtifacts(artifacts []agents.Artifact) ([]*spec.Event, error) {
+	var all []*spec.Event
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, EventDir) || !strings.HasSuffix(a.Filename, ".go") || strings.HasSuffix(a.Filename, "_test.go") {
+			continue
+		}
+		evs, err := spec.ParseEvents(a.Filename, a.Content)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", a.Filename, err)
+		}
+		all = append(all, evs...)
+	}
+	return all, nil
+}
+
+// AsyncAPI derives an AsyncAPI 3.0 catalog from the event structs the
+// Messaging agent wrote. Topics, directions and versions come from
+// //asyncapi: directives; missing directions are inferred from whether a
+// consumer references the struct. It returns nil when no events were generated.
+func AsyncAPI(svc *config.ServiceDefinition, artifacts []agents.Artifact) (*agents.Artifact, error) {
+	all, err := ParseEventArtifacts(artifacts)
+	if err != nil {
+		return nil, err
+	}
+	events, support := spec.ClassifyEvents(all)
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	inferDirections(events, artifacts)
+
+	out, err := spec.BuildAsyncAPI(svc.Name, events, support).YAML()
+	if err != nil {
+		return nil, fmt.Errorf("render asyncapi: %w", err)
+	}
+	return &agents.Artifact{
+		Filename: AsyncAPIPath,
+		Content:  "# Generated from " + EventDir + " by the agent pipeline. Do not edit.\n" + string(out),
+		Language: "yaml",
+	}, nil
+}
+
+// ConsumedEvents returns the event structs the service receives, by
+// directive or by reference from a consumer
+func ConsumedEvents(artifacts []agents.Artifact) ([]*spec.Event, error) {
+	all, err := ParseEventArtifacts(artifacts)
+	if err != nil {
+		return nil, err
+	}
+	events, _ := spec.ClassifyEvents(all)
+	inferDirections(events, artifacts)
+	var consumed []*spec.Event
+	for _, e := range events {
+		if e.Direction == spec.DirectionReceive {
+			consumed = append(consumed, e)
+		}
+	}
+	return consumed, nil
+}
+
+// inferDirections fills in missing directions: receive when a consumer
+// references the event, send otherwise
+func inferDirections(events []*spec.Event, artifacts []agents.Artifact) {
+	for _, e := range events {
+		if e.Direction != "" {
+			continue
+		}
+		e.Direction = spec.DirectionSend
+		for _, a := range artifacts {
+			if referencedIn(a, e.Name) {
+				e.Direction = spec.DirectionReceive
+				break
+			}
+		}
+	}
+}
+
+func referencedIn(a agents.Artifact, name string) bool {
+	for _, dir := range consumerDirs {
+		if strings.Contains(a.Filename, dir) && strings.Contains(a.Content, "."+name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,244 @@
+package postprocess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// Client SDK locations in the generated tree
+const (
+	GoClientDir = "pkg/client/"
+	TSClientDir = "clients/ts/"
+)
+
+// clientOp is an OpenAPI operation flattened into what a client method needs
+type clientOp struct {
+	Name    string // exported method name, e.g. GetOrder
+	Method  string
+	Path    string
+	Summary string
+
+	PathParams   []clientParam // in path order
+	QueryParams  []clientParam
+	HeaderParams []clientParam
+
+	Body         *spec.Schema
+	BodyRequired bool
+	Result       *spec.Schema // first 2xx JSON response, nil when the operation returns no body
+}
+
+// clientParam is a path, query or header parameter
+type clientParam struct {
+	Wire     string // name on the wire
+	Ident    string // exported identifier
+	Schema   *spec.Schema
+	Required bool
+}
+
+// Clients derives a typed Go client (pkg/client) and TypeScript client
+// (clients/ts) from api/openapi.yaml. It returns nil when the service has no
+// OpenAPI document.
+func Clients(svc *config.ServiceDefinition, artifacts []agents.Artifact) ([]agents.Artifact, error) {
+	var doc *spec.OpenAPI
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, "api/openapi.yaml") {
+			continue
+		}
+		d, err := spec.ParseOpenAPI([]byte(a.Content))
+		if err != nil {
+			return nil, err
+		}
+		doc = d
+	}
+	if doc == nil {
+		return nil, nil
+	}
+
+	ops := clientOps(doc)
+	goFiles, err := goClient(svc, doc, ops)
+	if err != nil {
+		return nil, fmt.Errorf("go client: %w", err)
+	}
+	return append(goFiles, tsClient(svc, doc, ops)...), nil
+}
+
+// clientOps flattens the document into uniquely named operations sorted by path then method
+func clientOps(doc *spec.OpenAPI) []clientOp {
+	var ops []clientOp
+	used := map[string]int{}
+	for _, ep := range doc.Endpoints() {
+		op := clientOp{Method: ep.Method, Path: ep.Path, Summary: ep.Operation.Summary}
+
+		op.Name = exportedName(ep.Operation.OperationID)
+		if op.Name == "" {
+			op.Name = exportedName(strings.ToLower(ep.Method) + " " + ep.Path)
+		}
+		if n := used[op.Name]; n > 0 {
+			used[op.Name]++
+			op.Name = fmt.Sprintf("%s%d", op.Name, n+1)
+		} else {
+			used[op.Name] = 1
+		}
+
+		byName := map[string]clientParam{}
+		for _, p := range doc.Parameters(ep) {
+			cp := clientParam{Wire: p.Name, Ident: exportedName(p.Name), Schema: p.Schema, Required: p.Required || p.In == "path"}
+			switch p.In {
+			case "path":
+				byName[p.Name] = cp
+			case "query":
+				op.QueryParams = append(op.QueryParams, cp)
+			case "header":
+				op.HeaderParams = append(op.HeaderParams, cp)
+			}
+		}
+		// Path parameters follow their order in the template; undeclared ones are strings
+		for _, name := range pathParamNames(ep.Path) {
+			cp, ok := byName[name]
+			if !ok {
+				cp = clientParam{Wire: name, Ident: exportedName(name), Required: true}
+			}
+			op.PathParams = append(op.PathParams, cp)
+		}
+
+		if rb := ep.Operation.RequestBody; rb != nil {
+			if mt, ok := jsonMedia(rb.Content); ok {
+				op.Body = mt.Schema
+				op.BodyRequired = rb.Required
+			}
+		}
+		codes := make([]string, 0, len(ep.Operation.Responses))
+		for code := range ep.Operation.Responses {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			if !strings.HasPrefix(code, "2") {
+				continue
+			}
+			if mt, ok := jsonMedia(ep.Operation.Responses[code].Content); ok && mt.Schema != nil {
+				op.Result = mt.Schema
+				break
+			}
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+func jsonMedia(content map[string]spec.MediaType) (spec.MediaType, bool) {
+	if mt, ok := content["application/json"]; ok {
+		return mt, true
+	}
+	for ct, mt := range content {
+		if strings.HasSuffix(ct, "+json") {
+			return mt, true
+		}
+	}
+	return spec.MediaType{}, false
+}
+
+// pathParamNames returns the {name} segments of a path template in order
+func pathParamNames(path string) []string {
+	var names []string
+	for {
+		open := strings.Index(path, "{")
+		if open < 0 {
+			return names
+		}
+		end := strings.Index(path[open:], "}")
+		if end < 0 {
+			return names
+		}
+		names = append(names, path[open+1:open+end])
+		path = path[open+end+1:]
+	}
+}
+
+// refName returns the component name a local $ref points at
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// initialisms are kept upper case in Go identifiers
+var initialisms = map[string]bool{"id": true, "url": true, "uri": true, "api": true, "http": true, "uuid": true, "json": true, "sku": true, "ip": true}
+
+// exportedName converts a wire name such as "order_id", "get-order" or
+// "listOrders" to an exported Go identifier ("OrderID", "GetOrder", "ListOrders")
+func exportedName(s string) string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, string(cur))
+			cur = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(cur) > 0 && (unicode.IsLower(cur[len(cur)-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+
+	var sb strings.Builder
+	for _, w := range words {
+		lower := strings.ToLower(w)
+		if initialisms[lower] {
+			sb.WriteString(strings.ToUpper(lower))
+			continue
+		}
+		sb.WriteString(strings.ToUpper(lower[:1]) + lower[1:])
+	}
+	out := sb.String()
+	if out != "" && unicode.IsDigit(rune(out[0])) {
+		out = "X" + out
+	}
+	return out
+}
+
+// camelName converts a wire name to a lower camel case identifier for TypeScript
+func camelName(s string) string {
+	name := exportedName(s)
+	for i, r := range name {
+		if !unicode.IsUpper(r) {
+			if i > 1 {
+				// Keep the last capital of a leading initialism: "IDValue" → "idValue"
+				i--
+			}
+			return strings.ToLower(name[:i]) + name[i:]
+		}
+	}
+	return strings.ToLower(name)
+}
+
+func sortedSchemaNames(m map[string]*spec.Schema) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func isNullable(s *spec.Schema) bool {
+	for _, t := range s.Type {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
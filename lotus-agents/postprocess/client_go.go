@@ -0,0 +1,547 @@
+package postprocess
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+const generatedHeader = "// Code generated from api/openapi.yaml by the agent pipeline. DO NOT EDIT.\n\n"
+
+// goModels renders OpenAPI schemas as Go types. Component schemas become
+// named types; inline objects become named types derived from their parent.
+type goModels struct {
+	doc   *spec.OpenAPI
+	decls map[string]string
+	order []string
+}
+
+func goClient(svc *config.ServiceDefinition, doc *spec.OpenAPI, ops []clientOp) ([]agents.Artifact, error) {
+	m := &goModels{doc: doc, decls: map[string]string{}}
+	for _, name := range sortedSchemaNames(doc.Components.Schemas) {
+		m.declare(exportedName(name), doc.Components.Schemas[name])
+	}
+
+	var opsSrc strings.Builder
+	for _, op := range ops {
+		opsSrc.WriteString(m.operation(op))
+	}
+
+	var models strings.Builder
+	for _, name := range m.order {
+		models.WriteString(m.decls[name])
+	}
+	modelsSrc := models.String()
+	var imports []string
+	if strings.Contains(modelsSrc, "json.RawMessage") {
+		imports = append(imports, `"encoding/json"`)
+	}
+	if strings.Contains(modelsSrc, "time.Time") {
+		imports = append(imports, `"time"`)
+	}
+	modelsFile := generatedHeader + "package client\n\n"
+	if len(imports) > 0 {
+		modelsFile += "import (\n" + strings.Join(imports, "\n") + "\n)\n\n"
+	}
+	modelsFile += modelsSrc
+
+	opsImports := []string{`"context"`, `"net/http"`, `"net/url"`}
+	if strings.Contains(opsSrc.String(), "fmt.") {
+		opsImports = append(opsImports, `"fmt"`)
+	}
+	if strings.Contains(opsSrc.String(), "json.RawMessage") {
+		opsImports = append(opsImports, `"encoding/json"`)
+	}
+	if strings.Contains(opsSrc.String(), "time.Time") {
+		opsImports = append(opsImports, `"time"`)
+	}
+	opsFile := generatedHeader + "package client\n\nimport (\n" + strings.Join(opsImports, "\n") + "\n)\n\n" + opsSrc.String()
+
+	clientFile := generatedHeader + fmt.Sprintf(goClientRuntime, svc.Name, defaultServerURL(doc))
+
+	var out []agents.Artifact
+	for _, f := range []struct{ name, src string }{
+		{"client.go", clientFile},
+		{"models.go", modelsFile},
+		{"operations.go", opsFile},
+	} {
+		formatted, err := format.Source([]byte(f.src))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		out = append(out, agents.Artifact{Filename: GoClientDir + f.name, Language: "go", Content: string(formatted)})
+	}
+	return out, nil
+}
+
+// declare emits a named type for a schema unless one already exists
+func (m *goModels) declare(name string, s *spec.Schema) {
+	if _, ok := m.decls[name]; ok {
+		return
+	}
+	m.decls[name] = "" // reserve before recursing into self-references
+	m.order = append(m.order, name)
+
+	var sb strings.Builder
+	switch {
+	case s == nil:
+		sb.WriteString(fmt.Sprintf("type %s = json.RawMessage\n\n", name))
+	case len(s.AllOf) > 0:
+		sb.WriteString(fmt.Sprintf("type %s struct {\n", name))
+		for _, part := range s.AllOf {
+			if part.Ref != "" {
+				sb.WriteString(exportedName(refName(part.Ref)) + "\n")
+				continue
+			}
+			sb.WriteString(m.fields(name, part))
+		}
+		sb.WriteString("}\n\n")
+	case s.Ref != "":
+		sb.WriteString(fmt.Sprintf("type %s = %s\n\n", name, exportedName(refName(s.Ref))))
+	case s.Type.Primary() == "string" && len(s.Enum) > 0:
+		sb.WriteString(fmt.Sprintf("type %s string\n\nconst (\n", name))
+		for _, v := range s.Enum {
+			lit := fmt.Sprint(v)
+			sb.WriteString(fmt.Sprintf("%s%s %s = %q\n", name, exportedName(lit), name, lit))
+		}
+		sb.WriteString(")\n\n")
+	case s.Type.Primary() == "object" || s.Type.Primary() == "" && len(s.Properties) > 0:
+		if len(s.Properties) == 0 {
+			sb.WriteString(fmt.Sprintf("type %s map[string]any\n\n", name))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("type %s struct {\n%s}\n\n", name, m.fields(name, s)))
+	default:
+		sb.WriteString(fmt.Sprintf("type %s %s\n\n", name, m.typeOf(s, name+"Item")))
+	}
+	m.decls[name] = sb.String()
+}
+
+// fields renders the properties of an object schema as struct fields
+func (m *goModels) fields(parent string, s *spec.Schema) string {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	var sb strings.Builder
+	for _, prop := range sortedSchemaNames(s.Properties) {
+		ps := s.Properties[prop]
+		field := exportedName(prop)
+		typ := m.typeOf(ps, parent+field)
+		optional := !required[prop]
+		if (optional || ps != nil && isNullable(ps)) && !isReferenceType(typ) {
+			typ = "*" + typ
+		}
+		tag := prop
+		if optional {
+			tag += ",omitempty"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s `json:%q`\n", field, typ, tag))
+	}
+	return sb.String()
+}
+
+// typeOf returns the Go type for a schema; hint names any inline object type it must declare
+func (m *goModels) typeOf(s *spec.Schema, hint string) string {
+	if s == nil {
+		return "json.RawMessage"
+	}
+	if s.Ref != "" {
+		return exportedName(refName(s.Ref))
+	}
+	if len(s.AllOf) > 0 {
+		if len(s.AllOf) == 1 && s.AllOf[0].Ref != "" {
+			return exportedName(refName(s.AllOf[0].Ref))
+		}
+		m.declare(hint, s)
+		return hint
+	}
+	if len(s.OneOf) > 0 || len(s.AnyOf) > 0 {
+		return "json.RawMessage"
+	}
+	switch s.Type.Primary() {
+	case "string":
+		if s.Format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		if s.Format == "int32" {
+			return "int32"
+		}
+		return "int64"
+	case "number":
+		if s.Format == "float" {
+			return "float32"
+		}
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]" + m.typeOf(s.Items, hint+"Item")
+	case "object", "":
+		if len(s.Properties) == 0 {
+			if s.Type.Primary() == "" {
+				return "json.RawMessage"
+			}
+			return "map[string]any"
+		}
+		m.declare(hint, s)
+		return hint
+	}
+	return "json.RawMessage"
+}
+
+func isReferenceType(t string) bool {
+	return strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || t == "json.RawMessage" || t == "any"
+}
+
+// operation renders the client method for one operation, plus its params type
+func (m *goModels) operation(op clientOp) string {
+	var sb strings.Builder
+
+	params := append(append([]clientParam{}, op.QueryParams...), op.HeaderParams...)
+	paramsType := op.Name + "Params"
+	if len(params) > 0 {
+		sb.WriteString(fmt.Sprintf("// %s holds the query and header parameters of %s.\ntype %s struct {\n", paramsType, op.Name, paramsType))
+		for _, p := range params {
+			typ := m.typeOf(p.Schema, paramsType+p.Ident)
+			if !isReferenceType(typ) {
+				typ = "*" + typ
+			}
+			sb.WriteString(fmt.Sprintf("%s %s\n", p.Ident, typ))
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range op.PathParams {
+		typ := "string"
+		if p.Schema != nil {
+			typ = m.typeOf(p.Schema, paramsType+p.Ident)
+		}
+		args = append(args, fmt.Sprintf("%s %s", goParamName(p.Wire), typ))
+	}
+	if op.Body != nil {
+		bodyType := m.typeOf(op.Body, op.Name+"Request")
+		if !isReferenceType(bodyType) {
+			bodyType = "*" + bodyType
+		}
+		args = append(args, "body "+bodyType)
+	}
+	if len(params) > 0 {
+		args = append(args, "params *"+paramsType)
+	}
+
+	result, zero := "error", "err"
+	if op.Result != nil {
+		rt := m.typeOf(op.Result, op.Name+"Response")
+		if !isReferenceType(rt) {
+			rt = "*" + rt
+		}
+		result, zero = "("+rt+", error)", "nil, err"
+	}
+
+	doc := fmt.Sprintf("// %s calls %s %s.", op.Name, op.Method, op.Path)
+	if op.Summary != "" {
+		doc += "\n// " + strings.TrimSpace(strings.ReplaceAll(op.Summary, "\n", " "))
+	}
+	sb.WriteString(fmt.Sprintf("%s\nfunc (c *Client) %s(%s) %s {\n", doc, op.Name, strings.Join(args, ", "), result))
+
+	sb.WriteString("path := " + goPathExpr(op.Path) + "\n")
+	sb.WriteString("query := url.Values{}\nheader := http.Header{}\n")
+	if len(params) > 0 {
+		sb.WriteString("if params != nil {\n")
+		for _, p := range op.QueryParams {
+			sb.WriteString(goParamSetter("query.Add", p, m.typeOf(p.Schema, paramsType+p.Ident)))
+		}
+		for _, p := range op.HeaderParams {
+			sb.WriteString(goParamSetter("header.Add", p, m.typeOf(p.Schema, paramsType+p.Ident)))
+		}
+		sb.WriteString("}\n")
+	}
+	body := "nil"
+	if op.Body != nil {
+		body = "body"
+	}
+	if op.Result == nil {
+		sb.WriteString(fmt.Sprintf("return c.do(ctx, %q, path, query, header, %s, nil)\n}\n\n", op.Method, body))
+		return sb.String()
+	}
+	rt := m.typeOf(op.Result, op.Name+"Response")
+	if isReferenceType(rt) {
+		sb.WriteString(fmt.Sprintf("var out %s\nif err := c.do(ctx, %q, path, query, header, %s, &out); err != nil {\nreturn %s\n}\nreturn out, nil\n}\n\n", rt, op.Method, body, zero))
+	} else {
+		sb.WriteString(fmt.Sprintf("out := new(%s)\nif err := c.do(ctx, %q, path, query, header, %s, out); err != nil {\nreturn %s\n}\nreturn out, nil\n}\n\n", rt, op.Method, body, zero))
+	}
+	return sb.String()
+}
+
+// goParamSetter adds a query or header parameter when it is set
+func goParamSetter(add string, p clientParam, typ string) string {
+	if strings.HasPrefix(typ, "[]") {
+		return fmt.Sprintf("for _, v := range params.%s {\n%s(%q, fmt.Sprint(v))\n}\n", p.Ident, add, p.Wire)
+	}
+	if isReferenceType(typ) {
+		return fmt.Sprintf("if params.%s != nil {\n%s(%q, fmt.Sprint(params.%s))\n}\n", p.Ident, add, p.Wire, p.Ident)
+	}
+	return fmt.Sprintf("if params.%s != nil {\n%s(%q, fmt.Sprint(*params.%s))\n}\n", p.Ident, add, p.Wire, p.Ident)
+}
+
+// goPathExpr renders a path template as a Go string expression with escaped parameters
+func goPathExpr(path string) string {
+	var parts []string
+	for {
+		open := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if open < 0 || end < open {
+			break
+		}
+		if open > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:open]))
+		}
+		parts = append(parts, fmt.Sprintf("url.PathEscape(fmt.Sprint(%s))", goParamName(path[open+1:end])))
+		path = path[end+1:]
+	}
+	if path != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", path))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// goKeywords cannot be used as parameter names
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true, "goto": true,
+	"if": true, "import": true, "interface": true, "map": true, "package": true, "range": true,
+	"return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+	// Names used inside generated methods
+	"ctx": true, "path": true, "query": true, "header": true, "body": true, "params": true, "out": true,
+}
+
+func goParamName(wire string) string {
+	name := camelName(wire)
+	if goKeywords[name] || name == "" {
+		name += "Param"
+	}
+	return name
+}
+
+func defaultServerURL(doc *spec.OpenAPI) string {
+	if len(doc.Servers) > 0 {
+		return strings.TrimSuffix(doc.Servers[0].URL, "/")
+	}
+	return "http://localhost:8080"
+}
+
+// goClientRuntime is the hand-written transport shared by every generated
+// operation: base URL, auth header injection, retries with backoff and
+// typed API errors. It is formatted with the service name and default URL.
+const goClientRuntime = `// Package client is a typed HTTP client for the %[1]s service.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the first server declared in the OpenAPI document.
+const DefaultBaseURL = %[2]q
+
+// TokenSource supplies the bearer token sent with every request.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that always returns the same token.
+type StaticToken string
+
+func (t StaticToken) Token(context.Context) (string, error) { return string(t), nil }
+
+// RetryPolicy controls how failed requests are retried. Only idempotent
+// methods, or requests carrying an Idempotency-Key header, are retried.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy retries up to three times with exponential backoff.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+// APIError is returned for non-2xx responses.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%[1]s: HTTP %%d: %%s", e.StatusCode, strings.TrimSpace(string(e.Body)))
+}
+
+// Client calls the %[1]s API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	tokens     TokenSource
+	retry      RetryPolicy
+	userAgent  string
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client.
+func WithHTTPClient(hc *http.Client) Option { return func(c *Client) { c.httpClient = hc } }
+
+// WithTokenSource injects "Authorization: Bearer <token>" into every request.
+func WithTokenSource(ts TokenSource) Option { return func(c *Client) { c.tokens = ts } }
+
+// WithBearerToken injects a fixed bearer token into every request.
+func WithBearerToken(token string) Option { return WithTokenSource(StaticToken(token)) }
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option { return func(c *Client) { c.retry = p } }
+
+// WithUserAgent sets the User-Agent header.
+func WithUserAgent(ua string) Option { return func(c *Client) { c.userAgent = ua } }
+
+// New returns a client for the API at baseURL (DefaultBaseURL when empty).
+func New(baseURL string, opts ...Option) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		retry:      DefaultRetryPolicy,
+		userAgent:  "%[1]s-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encode request: %%w", err)
+		}
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	retryable := header.Get("Idempotency-Key") != ""
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		retryable = true
+	}
+	attempts := max(c.retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, target, header, payload)
+		if err == nil && resp.StatusCode < 300 {
+			return decode(resp, out)
+		}
+
+		var wait time.Duration
+		if err == nil {
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+			resp.Body.Close()
+			err = &APIError{StatusCode: resp.StatusCode, Body: data}
+			switch resp.StatusCode {
+			case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				if s, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil {
+					wait = time.Duration(s) * time.Second
+				}
+			default:
+				return err
+			}
+		} else if ctx.Err() != nil {
+			return err
+		}
+		if !retryable || attempt >= attempts {
+			return err
+		}
+		if wait == 0 {
+			wait = c.backoff(attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, target string, header http.Header, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.tokens != nil {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get token: %%w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(req)
+}
+
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("decode response: %%w", err)
+	}
+	return nil
+}
+
+// backoff returns the exponential delay with full jitter for an attempt
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.retry.BaseDelay << (attempt - 1)
+	if d <= 0 || c.retry.MaxDelay > 0 && d > c.retry.MaxDelay {
+		d = c.retry.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(d)))
+}
+`
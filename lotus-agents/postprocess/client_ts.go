@@ -0,0 +1,435 @@
+package postprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+const tsGeneratedHeader = "// Code generated from api/openapi.yaml by the agent pipeline. DO NOT EDIT.\n\n"
+
+func tsClient(svc *config.ServiceDefinition, doc *spec.OpenAPI, ops []clientOp) []agents.Artifact {
+	var models strings.Builder
+	models.WriteString(tsGeneratedHeader)
+	for _, name := range sortedSchemaNames(doc.Components.Schemas) {
+		s := doc.Components.Schemas[name]
+		ident := exportedName(name)
+		if s != nil && len(s.Properties) > 0 && len(s.AllOf) == 0 {
+			models.WriteString(fmt.Sprintf("export interface %s %s\n\n", ident, tsObject(s, "")))
+			continue
+		}
+		models.WriteString(fmt.Sprintf("export type %s = %s;\n\n", ident, tsType(s, "")))
+	}
+
+	className := exportedName(svc.Name) + "Client"
+	var methods, paramTypes strings.Builder
+	for _, op := range ops {
+		params := append(append([]clientParam{}, op.QueryParams...), op.HeaderParams...)
+		paramsType := op.Name + "Params"
+		if len(params) > 0 {
+			paramTypes.WriteString(fmt.Sprintf("export interface %s {\n", paramsType))
+			for _, p := range params {
+				paramTypes.WriteString(fmt.Sprintf("  %s?: %s;\n", tsKey(p.Wire), tsType(p.Schema, "models.")))
+			}
+			paramTypes.WriteString("}\n\n")
+		}
+
+		var args []string
+		for _, p := range op.PathParams {
+			args = append(args, fmt.Sprintf("%s: %s", tsParamName(p.Wire), tsScalar(p.Schema)))
+		}
+		if op.Body != nil {
+			args = append(args, "body: "+tsType(op.Body, "models."))
+		}
+		if len(params) > 0 {
+			args = append(args, "params: "+paramsType+" = {}")
+		}
+		args = append(args, "signal?: AbortSignal")
+
+		result := "void"
+		if op.Result != nil {
+			result = tsType(op.Result, "models.")
+		}
+
+		methods.WriteString(fmt.Sprintf("  /** %s %s", op.Method, op.Path))
+		if op.Summary != "" {
+			methods.WriteString(" — " + strings.TrimSpace(strings.ReplaceAll(op.Summary, "\n", " ")))
+		}
+		methods.WriteString(" */\n")
+		methods.WriteString(fmt.Sprintf("  async %s(%s): Promise<%s> {\n", camelName(op.Name), strings.Join(args, ", "), result))
+
+		var query, header []string
+		for _, p := range op.QueryParams {
+			query = append(query, fmt.Sprintf("%q: params[%q]", p.Wire, p.Wire))
+		}
+		for _, p := range op.HeaderParams {
+			header = append(header, fmt.Sprintf("%q: params[%q]", p.Wire, p.Wire))
+		}
+		body := "undefined"
+		if op.Body != nil {
+			body = "body"
+		}
+		methods.WriteString(fmt.Sprintf("    return this.request<%s>(%q, %s, {\n", result, op.Method, tsPathExpr(op.Path)))
+		methods.WriteString(fmt.Sprintf("      query: %s,\n      headers: %s,\n      body: %s,\n      signal,\n    });\n  }\n\n",
+			tsLiteral(query), tsLiteral(header), body))
+	}
+
+	client := tsGeneratedHeader + fmt.Sprintf(tsClientRuntime, svc.Name, defaultServerURL(doc), className) +
+		tsIndent(paramTypes.String()) +
+		fmt.Sprintf("/** Typed client for the %s API. */\nexport class %s extends BaseClient {\n", svc.Name, className) +
+		strings.TrimSuffix(methods.String(), "\n") + "}\n"
+
+	version := doc.Info.Version
+	if version == "" {
+		version = "0.1.0"
+	}
+	pkg, _ := json.MarshalIndent(map[string]any{
+		"name":            "@" + svc.Name + "/client",
+		"version":         version,
+		"description":     fmt.Sprintf("Typed TypeScript client for the %s service", svc.Name),
+		"type":            "module",
+		"main":            "dist/index.js",
+		"types":           "dist/index.d.ts",
+		"files":           []string{"dist"},
+		"scripts":         map[string]string{"build": "tsc -p .", "prepublishOnly": "tsc -p ."},
+		"devDependencies": map[string]string{"typescript": "^5.4.0"},
+	}, "", "  ")
+
+	return []agents.Artifact{
+		{Filename: TSClientDir + "src/models.ts", Language: "typescript", Content: tsIndent(models.String())},
+		{Filename: TSClientDir + "src/client.ts", Language: "typescript", Content: client},
+		{Filename: TSClientDir + "src/index.ts", Language: "typescript", Content: tsGeneratedHeader + "export * from \"./client.js\";\nexport * from \"./models.js\";\n"},
+		{Filename: TSClientDir + "package.json", Language: "json", Content: string(pkg) + "\n"},
+		{Filename: TSClientDir + "tsconfig.json", Language: "json", Content: tsConfig},
+	}
+}
+
+// tsType returns the TypeScript type for a schema; prefix qualifies component references
+func tsType(s *spec.Schema, prefix string) string {
+	if s == nil {
+		return "unknown"
+	}
+	t := tsBaseType(s, prefix)
+	if isNullable(s) {
+		t += " | null"
+	}
+	return t
+}
+
+func tsBaseType(s *spec.Schema, prefix string) string {
+	if s.Ref != "" {
+		return prefix + exportedName(refName(s.Ref))
+	}
+	if len(s.AllOf) > 0 {
+		return tsJoin(s.AllOf, prefix, " & ")
+	}
+	if len(s.OneOf) > 0 {
+		return tsJoin(s.OneOf, prefix, " | ")
+	}
+	if len(s.AnyOf) > 0 {
+		return tsJoin(s.AnyOf, prefix, " | ")
+	}
+	if len(s.Enum) > 0 {
+		var lits []string
+		for _, v := range s.Enum {
+			b, _ := json.Marshal(v)
+			lits = append(lits, string(b))
+		}
+		return strings.Join(lits, " | ")
+	}
+	switch s.Type.Primary() {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		item := tsType(s.Items, prefix)
+		if strings.ContainsAny(item, "|&") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object", "":
+		if len(s.Properties) == 0 {
+			if s.Type.Primary() == "" {
+				return "unknown"
+			}
+			return "Record<string, unknown>"
+		}
+		return tsObject(s, prefix)
+	}
+	return "unknown"
+}
+
+func tsJoin(parts []*spec.Schema, prefix, sep string) string {
+	var types []string
+	for _, p := range parts {
+		t := tsType(p, prefix)
+		if strings.ContainsAny(t, "|&") {
+			t = "(" + t + ")"
+		}
+		types = append(types, t)
+	}
+	return strings.Join(types, sep)
+}
+
+// tsObject renders an inline object type; nested objects stay inline
+func tsObject(s *spec.Schema, prefix string) string {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	var sb strings.Builder
+	sb.WriteString("{\n")
+	for _, prop := range sortedSchemaNames(s.Properties) {
+		opt := "?"
+		if required[prop] {
+			opt = ""
+		}
+		sb.WriteString(fmt.Sprintf("  %s%s: %s;\n", tsKey(prop), opt, tsType(s.Properties[prop], prefix)))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+func tsLiteral(entries []string) string {
+	if len(entries) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
+
+// tsIndent re-indents rendered declarations by brace depth, since nested
+// object types are rendered without knowing their depth
+func tsIndent(src string) string {
+	var sb strings.Builder
+	depth := 0
+	for _, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		closing := strings.HasPrefix(line, "}")
+		if closing {
+			depth--
+		}
+		if line != "" {
+			sb.WriteString(strings.Repeat("  ", max(depth, 0)))
+		}
+		sb.WriteString(line + "\n")
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if closing {
+			depth++
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// tsScalar returns the type of a path parameter
+func tsScalar(s *spec.Schema) string {
+	if s != nil {
+		switch s.Type.Primary() {
+		case "integer", "number":
+			return "number"
+		}
+	}
+	return "string"
+}
+
+// tsKey quotes property names that are not valid identifiers
+func tsKey(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+// tsPathExpr renders a path template as a template literal with encoded parameters
+func tsPathExpr(path string) string {
+	var sb strings.Builder
+	sb.WriteString("`")
+	for {
+		open := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if open < 0 || end < open {
+			break
+		}
+		sb.WriteString(path[:open])
+		sb.WriteString("${encodeURIComponent(String(" + tsParamName(path[open+1:end]) + "))}")
+		path = path[end+1:]
+	}
+	sb.WriteString(path + "`")
+	return sb.String()
+}
+
+// tsReserved cannot be used as parameter names
+var tsReserved = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true, "continue": true, "default": true,
+	"delete": true, "do": true, "else": true, "enum": true, "export": true, "extends": true, "false": true,
+	"finally": true, "for": true, "function": true, "if": true, "import": true, "in": true, "instanceof": true,
+	"let": true, "new": true, "null": true, "return": true, "super": true, "switch": true, "this": true,
+	"throw": true, "true": true, "try": true, "typeof": true, "var": true, "void": true, "while": true,
+	"with": true, "yield": true,
+	// Names used by generated methods
+	"body": true, "params": true, "signal": true,
+}
+
+func tsParamName(wire string) string {
+	name := camelName(wire)
+	if tsReserved[name] || name == "" {
+		name += "Param"
+	}
+	return name
+}
+
+const tsConfig = `{
+  "compilerOptions": {
+    "target": "ES2022",
+    "module": "NodeNext",
+    "moduleResolution": "NodeNext",
+    "lib": ["ES2022", "DOM"],
+    "declaration": true,
+    "outDir": "dist",
+    "rootDir": "src",
+    "strict": true,
+    "skipLibCheck": true
+  },
+  "include": ["src"]
+}
+`
+
+// tsClientRuntime is the fetch-based transport the generated class extends:
+// base URL, bearer token injection, retries with backoff and typed errors.
+// It is formatted with the service name, default URL and class name.
+const tsClientRuntime = `import type * as models from "./models.js";
+
+/** First server declared in the OpenAPI document. */
+export const DEFAULT_BASE_URL = %[2]q;
+
+/** Supplies the bearer token sent with every request. */
+export type TokenProvider = string | (() => string | Promise<string>);
+
+export interface RetryPolicy {
+  maxAttempts: number;
+  baseDelayMs: number;
+  maxDelayMs: number;
+}
+
+export const DEFAULT_RETRY_POLICY: RetryPolicy = { maxAttempts: 3, baseDelayMs: 100, maxDelayMs: 2000 };
+
+export interface %[3]sOptions {
+  baseUrl?: string;
+  token?: TokenProvider;
+  retry?: RetryPolicy;
+  fetch?: typeof fetch;
+  headers?: Record<string, string>;
+}
+
+/** Thrown for non-2xx responses. */
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly body: string,
+  ) {
+    super(` + "`%[1]s: HTTP ${status}: ${body}`" + `);
+    this.name = "ApiError";
+  }
+}
+
+type Scalar = string | number | boolean;
+
+interface RequestOptions {
+  query?: Record<string, Scalar | Scalar[] | null | undefined>;
+  headers?: Record<string, Scalar | null | undefined>;
+  body?: unknown;
+  signal?: AbortSignal;
+}
+
+const RETRYABLE_STATUS = new Set([429, 502, 503, 504]);
+const IDEMPOTENT_METHODS = new Set(["GET", "HEAD", "PUT", "DELETE", "OPTIONS"]);
+
+export class BaseClient {
+  private readonly baseUrl: string;
+  private readonly token?: TokenProvider;
+  private readonly retry: RetryPolicy;
+  private readonly fetchImpl: typeof fetch;
+  private readonly defaultHeaders: Record<string, string>;
+
+  constructor(options: %[3]sOptions = {}) {
+    this.baseUrl = (options.baseUrl ?? DEFAULT_BASE_URL).replace(/\/+$/, "");
+    this.token = options.token;
+    this.retry = options.retry ?? DEFAULT_RETRY_POLICY;
+    this.fetchImpl = options.fetch ?? globalThis.fetch.bind(globalThis);
+    this.defaultHeaders = options.headers ?? {};
+  }
+
+  protected async request<T>(method: string, path: string, options: RequestOptions): Promise<T> {
+    const url = new URL(this.baseUrl + path);
+    for (const [key, value] of Object.entries(options.query ?? {})) {
+      if (value === undefined || value === null) continue;
+      for (const v of Array.isArray(value) ? value : [value]) url.searchParams.append(key, String(v));
+    }
+
+    const headers: Record<string, string> = { Accept: "application/json", ...this.defaultHeaders };
+    for (const [key, value] of Object.entries(options.headers ?? {})) {
+      if (value !== undefined && value !== null) headers[key] = String(value);
+    }
+    if (options.body !== undefined) headers["Content-Type"] = "application/json";
+    if (this.token !== undefined) {
+      const token = typeof this.token === "function" ? await this.token() : this.token;
+      headers["Authorization"] = ` + "`Bearer ${token}`" + `;
+    }
+
+    const retryable = IDEMPOTENT_METHODS.has(method) || "Idempotency-Key" in headers;
+    const attempts = Math.max(this.retry.maxAttempts, 1);
+    for (let attempt = 1; ; attempt++) {
+      let response: Response;
+      try {
+        response = await this.fetchImpl(url, {
+          method,
+          headers,
+          body: options.body === undefined ? undefined : JSON.stringify(options.body),
+          signal: options.signal,
+        });
+      } catch (err) {
+        if (!retryable || attempt >= attempts || options.signal?.aborted) throw err;
+        await sleep(this.backoff(attempt), options.signal);
+        continue;
+      }
+
+      if (response.ok) {
+        if (response.status === 204) return undefined as T;
+        const text = await response.text();
+        return (text ? JSON.parse(text) : undefined) as T;
+      }
+
+      const body = await response.text();
+      if (!RETRYABLE_STATUS.has(response.status) || !retryable || attempt >= attempts) {
+        throw new ApiError(response.status, body);
+      }
+      const retryAfter = Number(response.headers.get("Retry-After"));
+      await sleep(retryAfter > 0 ? retryAfter * 1000 : this.backoff(attempt), options.signal);
+    }
+  }
+
+  private backoff(attempt: number): number {
+    const delay = Math.min(this.retry.baseDelayMs * 2 ** (attempt - 1), this.retry.maxDelayMs);
+    return Math.random() * delay;
+  }
+}
+
+function sleep(ms: number, signal?: AbortSignal): Promise<void> {
+  return new Promise((resolve, reject) => {
+    const timer = setTimeout(resolve, ms);
+    signal?.addEventListener("abort", () => {
+      clearTimeout(timer);
+      reject(signal.reason);
+    });
+  });
+}
+
+`
@@ -0,0 +1,172 @@
+package postprocess
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+var yamlErrorLineRe = regexp.MustCompile(`line (\d+)`)
+
+// FormatProblem is a file Format could not fully clean up
+type FormatProblem struct {
+	File    string
+	Line    int
+	Message string
+
+	// Parse is set when the file did not parse and was left as generated
+	Parse bool
+}
+
+// Format returns the artifacts in the same order with Go files import-fixed
+// and gofmt'ed, SQL keywords upper-cased and whitespace normalised, YAML
+// re-indented and minified JSON expanded. Files that do not parse are
+// returned as generated.
+func Format(artifacts []agents.Artifact) ([]agents.Artifact, []FormatProblem) {
+	idx := newPackageIndex(artifacts)
+	out := make([]agents.Artifact, len(artifacts))
+	var problems []FormatProblem
+	for i, a := range artifacts {
+		out[i] = a
+		var content string
+		var p *FormatProblem
+		switch path.Ext(a.Filename) {
+		case ".go":
+			var missing []string
+			content, missing, p = formatGo(a, idx)
+			for _, name := range missing {
+				problems = append(problems, FormatProblem{File: a.Filename, Message: fmt.Sprintf("uses package %s, which is not imported and could not be resolved", name)})
+			}
+		case ".sql":
+			content = spec.FormatSQL(a.Content)
+		case ".yaml", ".yml":
+			content, p = formatYAML(a)
+		case ".json":
+			content, p = formatJSON(a)
+		default:
+			continue
+		}
+		if p != nil {
+			problems = append(problems, *p)
+			continue
+		}
+		out[i].Content = content
+	}
+	return out, problems
+}
+
+// formatGo fixes the imports and runs gofmt, returning the packages it could not import
+func formatGo(a agents.Artifact, idx *packageIndex) (string, []string, *FormatProblem) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, a.Filename, a.Content, parser.ParseComments)
+	if err != nil {
+		return "", nil, parseProblem(a.Filename, err)
+	}
+	fixed, missing := fixImports(fset, f, a.Content, a.Filename, idx)
+	out, err := format.Source([]byte(fixed))
+	if err != nil {
+		// The rewritten import block should always parse; fall back to plain gofmt
+		out, err = format.Source([]byte(a.Content))
+		if err != nil {
+			return "", nil, parseProblem(a.Filename, err)
+		}
+	}
+	return string(out), missing, nil
+}
+
+func parseProblem(file string, err error) *FormatProblem {
+	p := &FormatProblem{File: file, Message: err.Error(), Parse: true}
+	var list scanner.ErrorList
+	if errors.As(err, &list) && len(list) > 0 {
+		p.Line, p.Message = list[0].Pos.Line, list[0].Msg
+	}
+	return p
+}
+
+// formatYAML re-indents every document with two spaces, keeping comments
+// and key order. Templates (Helm, Go text/template) are left alone, as is
+// any file whose re-encoded form would decode differently.
+func formatYAML(a agents.Artifact) (string, *FormatProblem) {
+	if strings.Contains(a.Content, "{{") {
+		return a.Content, nil
+	}
+	dec := yaml.NewDecoder(strings.NewReader(a.Content))
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			p := &FormatProblem{File: a.Filename, Message: err.Error(), Parse: true}
+			if m := yamlErrorLineRe.FindStringSubmatch(p.Message); m != nil {
+				p.Line, _ = strconv.Atoi(m[1])
+			}
+			return "", p
+		}
+		if err := enc.Encode(&doc); err != nil {
+			return a.Content, nil
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return a.Content, nil
+	}
+	if !reflect.DeepEqual(yamlValues(a.Content), yamlValues(buf.String())) {
+		return a.Content, nil
+	}
+	return buf.String(), nil
+}
+
+// yamlValues decodes every document of a YAML stream
+func yamlValues(src string) []any {
+	dec := yaml.NewDecoder(strings.NewReader(src))
+	var docs []any
+	for {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return docs
+		}
+		docs = append(docs, v)
+	}
+}
+
+// formatJSON validates JSON and re-indents minified files with two spaces;
+// tsconfig files allow comments and are left alone
+func formatJSON(a agents.Artifact) (string, *FormatProblem) {
+	if strings.HasPrefix(path.Base(a.Filename), "tsconfig") {
+		return a.Content, nil
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(strings.TrimSpace(a.Content)), "", "  "); err != nil {
+		p := &FormatProblem{File: a.Filename, Message: err.Error(), Parse: true}
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			p.Line = 1 + strings.Count(strings.TrimSpace(a.Content)[:syntax.Offset], "\n")
+		}
+		return "", p
+	}
+	if strings.Contains(strings.TrimSpace(a.Content), "\n") {
+		return a.Content, nil
+	}
+	buf.WriteByte('\n')
+	return buf.String(), nil
+}
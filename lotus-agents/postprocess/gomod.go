@@ -0,0 +1,112 @@
+package postprocess
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+)
+
+// GoModPath is where the generated module file is written
+const GoModPath = "go.mod"
+
+// GoVersion is the language version the generated go.mod declares
+const GoVersion = "1.24"
+
+// GoModule rewrites imports that have a drop-in approved replacement or that
+// refer to the service's own packages under another module path, and derives
+// go.mod from the approved modules the code then imports. It returns the Go
+// artifacts in the same order and go.mod, or a nil go.mod when there is no
+// Go code. Unapproved imports are left for the dependency check to report.
+func GoModule(svc *config.ServiceDefinition, artifacts []agents.Artifact) ([]agents.Artifact, *agents.Artifact) {
+	module := svc.Module()
+	dirs := map[string]bool{}
+	for _, a := range artifacts {
+		if strings.HasSuffix(a.Filename, ".go") && path.Dir(a.Filename) != "." {
+			dirs[path.Dir(a.Filename)] = true
+		}
+	}
+	if len(dirs) == 0 {
+		return artifacts, nil
+	}
+
+	out := make([]agents.Artifact, len(artifacts))
+	required := map[string]config.Dependency{}
+	for i, a := range artifacts {
+		out[i] = a
+		if !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, a.Filename, a.Content, parser.ImportsOnly)
+		if err != nil {
+			continue // reported by the formatting check
+		}
+		content := a.Content
+		for j := len(f.Imports) - 1; j >= 0; j-- {
+			imp := f.Imports[j]
+			p, _ := strconv.Unquote(imp.Path.Value)
+			np := resolveImport(p, module, dirs)
+			if d, ok := config.ApprovedDependency(np); ok {
+				required[d.Path] = d
+			}
+			if np != p {
+				start, end := fset.Position(imp.Path.Pos()).Offset, fset.Position(imp.Path.End()).Offset
+				content = content[:start] + strconv.Quote(np) + content[end:]
+			}
+		}
+		out[i].Content = content
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("module %s\n\ngo %s\n", module, GoVersion))
+	if len(required) > 0 {
+		paths := make([]string, 0, len(required))
+		for p := range required {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		sb.WriteString("\nrequire (\n")
+		for _, p := range paths {
+			sb.WriteString(fmt.Sprintf("\t%s %s\n", p, required[p].Version))
+		}
+		sb.WriteString(")\n")
+	}
+	return out, &agents.Artifact{Filename: GoModPath, Language: "go.mod", Content: sb.String()}
+}
+
+// resolveImport returns the import path to use in place of p: the approved
+// drop-in replacement, or the service's own package when p ends in one of
+// the generated package directories under another module path
+func resolveImport(p, module string, dirs map[string]bool) string {
+	if isStdlib(p) || p == module || strings.HasPrefix(p, module+"/") {
+		return p
+	}
+	if _, ok := config.ApprovedDependency(p); ok {
+		return p
+	}
+	if np := config.DependencyRewrite(p); np != "" {
+		return np
+	}
+	best := ""
+	for d := range dirs {
+		if strings.HasSuffix(p, "/"+d) && len(d) > len(best) {
+			best = d
+		}
+	}
+	if best != "" {
+		return module + "/" + best
+	}
+	return p
+}
+
+// isStdlib reports whether an import path belongs to the standard library
+func isStdlib(importPath string) bool {
+	return !strings.Contains(strings.Split(importPath, "/")[0], ".")
+}
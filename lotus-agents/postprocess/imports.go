@@ -0,0 +1,392 @@
+package postprocess
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+// stdlibPackages maps the package names models use to their import paths;
+// rand, shared by math/rand and crypto/rand, is resolved by resolve
+var stdlibPackages = map[string]string{
+	"aes": "crypto/aes", "atomic": "sync/atomic", "base64": "encoding/base64", "big": "math/big",
+	"binary": "encoding/binary", "bits": "math/bits", "bufio": "bufio", "bytes": "bytes",
+	"cipher": "crypto/cipher", "cmp": "cmp", "context": "context", "csv": "encoding/csv",
+	"debug": "runtime/debug", "driver": "database/sql/driver", "ecdsa": "crypto/ecdsa",
+	"ed25519": "crypto/ed25519", "embed": "embed", "errors": "errors", "exec": "os/exec",
+	"filepath": "path/filepath", "flag": "flag", "fmt": "fmt", "fnv": "hash/fnv", "fs": "io/fs",
+	"gzip": "compress/gzip", "heap": "container/heap", "hex": "encoding/hex", "hmac": "crypto/hmac",
+	"html": "html", "http": "net/http", "httptest": "net/http/httptest", "httputil": "net/http/httputil",
+	"io": "io", "json": "encoding/json", "list": "container/list", "log": "log", "maps": "maps",
+	"math": "math", "md5": "crypto/md5", "mime": "mime", "multipart": "mime/multipart", "net": "net",
+	"netip": "net/netip", "os": "os", "path": "path", "pem": "encoding/pem", "reflect": "reflect",
+	"regexp": "regexp", "rsa": "crypto/rsa", "runtime": "runtime", "sha1": "crypto/sha1",
+	"sha256": "crypto/sha256", "sha512": "crypto/sha512", "signal": "os/signal", "slices": "slices",
+	"slog": "log/slog", "sort": "sort", "sql": "database/sql", "strconv": "strconv",
+	"strings": "strings", "subtle": "crypto/subtle", "sync": "sync", "syscall": "syscall",
+	"tabwriter": "text/tabwriter", "testing": "testing", "time": "time", "tls": "crypto/tls",
+	"unicode": "unicode", "url": "net/url", "utf8": "unicode/utf8", "x509": "crypto/x509",
+}
+
+// randNames are the crypto/rand identifiers: true for those only crypto/rand
+// has, false for those math/rand has too. Any other rand.X is math/rand.
+var randNames = map[string]bool{"Reader": true, "Prime": true, "Text": true, "Int": false, "Read": false}
+
+var versionSuffixRe = regexp.MustCompile(`^v[0-9]+$`)
+
+// goPackage is a package of the generated tree
+type goPackage struct {
+	name string
+	path string // import path, empty when the tree has no go.mod
+
+	// decls are the package-level names across all its files; exported are
+	// those of its non-test files that other packages can use
+	decls    map[string]bool
+	exported map[string]bool
+}
+
+// packageIndex finds the generated packages by directory and by import path
+type packageIndex struct {
+	module string
+	byDir  map[string]*goPackage // key: dir + " " + package name
+	byPath map[string]*goPackage
+}
+
+func newPackageIndex(artifacts []agents.Artifact) *packageIndex {
+	idx := &packageIndex{byDir: map[string]*goPackage{}, byPath: map[string]*goPackage{}}
+	for _, a := range artifacts {
+		if a.Filename == "go.mod" {
+			idx.module = goModulePath(a.Content)
+		}
+	}
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), a.Filename, a.Content, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		dir := path.Dir(a.Filename)
+		key := dir + " " + f.Name.Name
+		pkg := idx.byDir[key]
+		if pkg == nil {
+			pkg = &goPackage{name: f.Name.Name, decls: map[string]bool{}, exported: map[string]bool{}}
+			idx.byDir[key] = pkg
+			if idx.module != "" && !strings.HasSuffix(f.Name.Name, "_test") && f.Name.Name != "main" {
+				pkg.path = idx.module
+				if dir != "." {
+					pkg.path += "/" + dir
+				}
+				idx.byPath[pkg.path] = pkg
+			}
+		}
+		test := strings.HasSuffix(a.Filename, "_test.go")
+		for _, name := range topLevelNames(f) {
+			pkg.decls[name] = true
+			if !test && ast.IsExported(name) {
+				pkg.exported[name] = true
+			}
+		}
+	}
+	return idx
+}
+
+// topLevelNames lists the package-level functions, types, variables and constants of f
+func topLevelNames(f *ast.File) []string {
+	var names []string
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names = append(names, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					names = append(names, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						names = append(names, n.Name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// goModulePath returns the module path declared in a go.mod file
+func goModulePath(gomod string) string {
+	for _, line := range strings.Split(gomod, "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
+
+// nameFor returns the package name an import path is referred to by; certain
+// is false when the name is guessed from the path of an unknown module
+func (idx *packageIndex) nameFor(importPath string) (name string, certain bool) {
+	if pkg, ok := idx.byPath[importPath]; ok {
+		return pkg.name, true
+	}
+	elems := strings.Split(importPath, "/")
+	last := elems[len(elems)-1]
+	if len(elems) > 1 && versionSuffixRe.MatchString(last) {
+		last = elems[len(elems)-2]
+	}
+	if isStdlib(importPath) {
+		return last, true
+	}
+	last = strings.TrimPrefix(last, "go-")
+	last = strings.TrimSuffix(strings.TrimSuffix(last, "-go"), ".go")
+	if i := strings.Index(last, ".v"); i > 0 {
+		last = last[:i] // gopkg.in/yaml.v3
+	}
+	return strings.NewReplacer("-", "", ".", "").Replace(last), false
+}
+
+// resolve returns the import path that provides every selector used on
+// name, preferring a generated package over the standard library
+func (idx *packageIndex) resolve(name string, sels map[string]bool) string {
+	var local []string
+	for _, pkg := range idx.byPath {
+		if pkg.name != name {
+			continue
+		}
+		all := true
+		for sel := range sels {
+			all = all && pkg.exported[sel]
+		}
+		if all {
+			local = append(local, pkg.path)
+		}
+	}
+	if len(local) == 1 {
+		return local[0]
+	}
+	if len(local) > 1 {
+		return "" // ambiguous
+	}
+	if name == "rand" {
+		cryptoOnly, mathOnly := false, false
+		for sel := range sels {
+			only, shared := randNames[sel]
+			cryptoOnly = cryptoOnly || only
+			mathOnly = mathOnly || !shared
+		}
+		switch {
+		case cryptoOnly && !mathOnly:
+			return "crypto/rand"
+		case mathOnly && !cryptoOnly:
+			return "math/rand"
+		}
+		return "" // only names both packages have, or a mix
+	}
+	return stdlibPackages[name]
+}
+
+// importSpec is one import line as it will be written
+type importSpec struct {
+	name, path string
+	doc        []string
+	comment    string
+	keep       bool // used, blank, dot or added
+	certain    bool
+}
+
+// fixImports removes unused imports, adds missing ones it can resolve and
+// regroups the imports into standard library, third-party and module
+// blocks. It returns the new source and the package names it could not
+// resolve. Files importing "C" are returned unchanged.
+func fixImports(fset *token.FileSet, f *ast.File, src, filename string, idx *packageIndex) (string, []string) {
+	for _, imp := range f.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p == "C" {
+			return src, nil
+		}
+	}
+
+	own := idx.byDir[path.Dir(filename)+" "+f.Name.Name]
+	qualifiers := map[string]map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil && (own == nil || !own.decls[x.Name]) {
+			if qualifiers[x.Name] == nil {
+				qualifiers[x.Name] = map[string]bool{}
+			}
+			qualifiers[x.Name][sel.Sel.Name] = true
+		}
+		return true
+	})
+
+	var specs []*importSpec
+	var original []string
+	provided := map[string]bool{}
+	group, prevLine := 0, 0
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.IMPORT {
+			continue
+		}
+		if len(specs) > 0 {
+			group, prevLine = group+1, 0
+		}
+		for _, s := range gd.Specs {
+			is := s.(*ast.ImportSpec)
+			p, _ := strconv.Unquote(is.Path.Value)
+			spec := &importSpec{path: p}
+			name, certain := idx.nameFor(p)
+			if is.Name != nil {
+				spec.name, name, certain = is.Name.Name, is.Name.Name, true
+			}
+			spec.certain = certain
+			spec.keep = name == "_" || name == "." || qualifiers[name] != nil
+			provided[name] = true
+			if is.Doc != nil {
+				for _, c := range is.Doc.List {
+					spec.doc = append(spec.doc, c.Text)
+				}
+			}
+			if is.Comment != nil {
+				spec.comment = is.Comment.List[0].Text
+			}
+			line := fset.Position(is.Pos()).Line
+			if is.Doc != nil {
+				line = fset.Position(is.Doc.Pos()).Line
+			}
+			if prevLine > 0 && line > prevLine+1 {
+				group++
+			}
+			prevLine = fset.Position(is.End()).Line
+			original = append(original, fmt.Sprintf("%d %s %s", group, spec.name, p))
+			specs = append(specs, spec)
+		}
+	}
+
+	var missing []string
+	for name, sels := range qualifiers {
+		if provided[name] {
+			continue
+		}
+		if p := idx.resolve(name, sels); p != "" {
+			spec := &importSpec{path: p, keep: true, certain: true}
+			if n, _ := idx.nameFor(p); n != name {
+				spec.name = name
+			}
+			specs = append(specs, spec)
+			continue
+		}
+		if s := guessedImport(specs, name); s != nil {
+			s.keep = true // e.g. anthropic from github.com/anthropics/anthropic-sdk-go
+			continue
+		}
+		missing = append(missing, name)
+	}
+	sort.Strings(missing)
+
+	// A guessed name may be wrong; keep such imports while anything is unresolved
+	var kept []*importSpec
+	for _, s := range specs {
+		if s.keep || !s.certain && len(missing) > 0 {
+			kept = append(kept, s)
+		}
+	}
+
+	groups := make([][]*importSpec, 3)
+	for _, s := range kept {
+		g := 1
+		switch {
+		case isStdlib(s.path):
+			g = 0
+		case idx.module != "" && (s.path == idx.module || strings.HasPrefix(s.path, idx.module+"/")):
+			g = 2
+		}
+		groups[g] = append(groups[g], s)
+	}
+	var canonical []string
+	var block strings.Builder
+	n := 0
+	for _, g := range groups {
+		if len(g) == 0 {
+			continue
+		}
+		sort.SliceStable(g, func(i, j int) bool { return g[i].path < g[j].path })
+		if n > 0 {
+			block.WriteString("\n")
+		}
+		for _, s := range g {
+			canonical = append(canonical, fmt.Sprintf("%d %s %s", n, s.name, s.path))
+			for _, d := range s.doc {
+				block.WriteString("\t" + d + "\n")
+			}
+			block.WriteString("\t")
+			if s.name != "" {
+				block.WriteString(s.name + " ")
+			}
+			block.WriteString(strconv.Quote(s.path))
+			if s.comment != "" {
+				block.WriteString(" " + s.comment)
+			}
+			block.WriteString("\n")
+		}
+		n++
+	}
+	if strings.Join(canonical, "\n") == strings.Join(original, "\n") {
+		return src, missing
+	}
+
+	text := ""
+	switch {
+	case len(kept) == 1 && len(kept[0].doc) == 0:
+		text = "import " + strings.TrimSpace(block.String())
+	case len(kept) > 0:
+		text = "import (\n" + block.String() + ")"
+	}
+	start, end := -1, -1
+	for _, d := range f.Decls {
+		if gd, ok := d.(*ast.GenDecl); ok && gd.Tok == token.IMPORT {
+			if start < 0 {
+				start = fset.Position(gd.Pos()).Offset
+			}
+			end = fset.Position(gd.End()).Offset
+		}
+	}
+	if start < 0 {
+		start = fset.Position(f.Name.End()).Offset
+		end, text = start, "\n\n"+text
+	}
+	return src[:start] + text + src[end:], missing
+}
+
+// guessedImport returns the import with a guessed name whose last path
+// element contains name, as that is most likely the package it refers to
+func guessedImport(specs []*importSpec, name string) *importSpec {
+	for _, s := range specs {
+		if s.certain {
+			continue
+		}
+		elems := strings.Split(s.path, "/")
+		last := elems[len(elems)-1]
+		if len(elems) > 1 && versionSuffixRe.MatchString(last) {
+			last = elems[len(elems)-2]
+		}
+		if strings.Contains(strings.ToLower(last), strings.ToLower(name)) {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package sandbox
+
+import (
+	"context"
+	"io"
+	"os/exec"
+)
+
+// systemDirs are bound read-only so the toolchain finds its libraries and compilers
+var systemDirs = []string{"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/etc"}
+
+// Bubblewrap runs the command in fresh user, network, PID, IPC and UTS
+// namespaces with only the system directories, Cmd.Dir and Cmd.ReadOnly
+// visible read-only and Cmd.Writable read-write
+type Bubblewrap struct{}
+
+func (*Bubblewrap) Name() string { return "bwrap" }
+
+// Available runs a trivial command, since unprivileged user namespaces are often disabled
+func (*Bubblewrap) Available() error {
+	if _, err := exec.LookPath("bwrap"); err != nil {
+		return err
+	}
+	out, err := exec.Command("bwrap", "--unshare-all", "--ro-bind", "/", "/", "true").CombinedOutput()
+	if err != nil {
+		return commandError(err, out)
+	}
+	return nil
+}
+
+func (*Bubblewrap) Run(ctx context.Context, cmd Command, stdout, stderr io.Writer) error {
+	args := []string{"--unshare-all", "--die-with-parent", "--new-session"}
+	for _, d := range systemDirs {
+		args = append(args, "--ro-bind-try", d, d)
+	}
+	args = append(args, "--proc", "/proc", "--dev", "/dev", "--tmpfs", "/tmp")
+	for _, d := range append([]string{cmd.Dir}, cmd.ReadOnly...) {
+		args = append(args, "--ro-bind", d, d)
+	}
+	for _, d := range cmd.Writable {
+		args = append(args, "--bind", d, d)
+	}
+	args = append(args, "--chdir", cmd.Dir, "--")
+	args = append(args, withRlimits(cmd.Limits, cmd.Args)...)
+	return run(ctx, cmd.Limits, "bwrap", args, cmd.Dir, cmd.Env, stdout, stderr, nil)
+}
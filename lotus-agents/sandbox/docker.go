@@ -0,0 +1,76 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultImage provides the Go toolchain and a C compiler for the race detector
+const DefaultImage = "golang:1.24"
+
+// pidsLimit bounds the processes a container may start
+const pidsLimit = 4096
+
+// Docker runs the command in a throwaway container without network access,
+// capabilities or a writable root filesystem. Directories are mounted at
+// their host paths. PATH and GOROOT are left to the image, so the host
+// toolchain is only used if it is mounted over the image's.
+type Docker struct {
+	Image string
+
+	// rootless is set by Available; a rootful daemon runs the command as the calling user instead
+	rootless bool
+}
+
+func (d *Docker) Name() string { return "docker" }
+
+func (d *Docker) Available() error {
+	out, err := exec.Command("docker", "info", "--format", "{{.SecurityOptions}}").CombinedOutput()
+	if err != nil {
+		return commandError(err, out)
+	}
+	d.rootless = strings.Contains(string(out), "rootless")
+	return nil
+}
+
+func (d *Docker) Run(ctx context.Context, cmd Command, stdout, stderr io.Writer) error {
+	name := fmt.Sprintf("lotus-sandbox-%d", time.Now().UnixNano())
+	args := []string{"run", "--rm", "--name", name,
+		"--network", "none", "--read-only", "--tmpfs", "/tmp",
+		"--cap-drop", "ALL", "--security-opt", "no-new-privileges",
+		"--pids-limit", fmt.Sprint(pidsLimit),
+	}
+	if !d.rootless {
+		args = append(args, "--user", fmt.Sprintf("%d:%d", os.Getuid(), os.Getgid()))
+	}
+	if l := cmd.Limits; l.Memory > 0 {
+		args = append(args, "--memory", fmt.Sprint(l.Memory), "--memory-swap", fmt.Sprint(l.Memory))
+	}
+	if l := cmd.Limits; l.CPUTime > 0 {
+		secs := int64(l.CPUTime / time.Second)
+		args = append(args, "--ulimit", fmt.Sprintf("cpu=%d:%d", secs, secs))
+	}
+	for _, dir := range append([]string{cmd.Dir}, cmd.ReadOnly...) {
+		args = append(args, "-v", dir+":"+dir+":ro")
+	}
+	for _, dir := range cmd.Writable {
+		args = append(args, "-v", dir+":"+dir)
+	}
+	for _, kv := range cmd.Env {
+		if k, _, _ := strings.Cut(kv, "="); k == "PATH" || k == "GOROOT" {
+			continue
+		}
+		args = append(args, "-e", kv)
+	}
+	args = append(args, "-w", cmd.Dir, d.Image)
+	args = append(args, cmd.Args...)
+
+	// The docker client is killed on timeout but the container is not
+	cleanup := func() { exec.Command("docker", "rm", "-f", name).Run() }
+	return run(ctx, cmd.Limits, "docker", args, "", os.Environ(), stdout, stderr, cleanup)
+}
@@ -0,0 +1,21 @@
+package sandbox
+
+import (
+	"context"
+	"io"
+)
+
+// Process runs the command as a plain subprocess with rlimits. It has no
+// filesystem or network isolation of its own: the caller's environment and
+// file permissions are all that keep the command away from the network and
+// the source tree.
+type Process struct{}
+
+func (*Process) Name() string { return "process" }
+
+func (*Process) Available() error { return nil }
+
+func (*Process) Run(ctx context.Context, cmd Command, stdout, stderr io.Writer) error {
+	args := withRlimits(cmd.Limits, cmd.Args)
+	return run(ctx, cmd.Limits, args[0], args[1:], cmd.Dir, cmd.Env, stdout, stderr, nil)
+}
@@ -0,0 +1,147 @@
+// Package sandbox runs verification commands against model-written code with
+// restricted privileges: no network, bounded CPU time, memory and wall time,
+// and the source tree mounted read-only. Runner implementations wrap
+// bubblewrap, a docker container, or a plain subprocess with rlimits.
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrTimeout is returned when a command exceeds its wall-clock limit
+var ErrTimeout = errors.New("wall-clock limit exceeded")
+
+// Limits bounds the resources a command may use; zero values mean no limit
+type Limits struct {
+	// CPUTime is the CPU time each process may use (RLIMIT_CPU)
+	CPUTime time.Duration
+
+	// Memory is the data segment each process may use (RLIMIT_DATA), or the
+	// memory of the whole container under docker, in bytes
+	Memory int64
+
+	// Wall bounds the whole command
+	Wall time.Duration
+}
+
+// DefaultLimits suit compiling and testing a generated service
+var DefaultLimits = Limits{
+	CPUTime: 15 * time.Minute,
+	Memory:  4 << 30,
+	Wall:    10 * time.Minute,
+}
+
+// Command is one verification command
+type Command struct {
+	// Args is the program and its arguments
+	Args []string
+
+	// Dir is the working directory; it is mounted read-only
+	Dir string
+
+	// Env is the complete environment; nothing is inherited
+	Env []string
+
+	// ReadOnly are further host directories the command may read, e.g. the Go
+	// toolchain and module cache. Writable are the only directories it may
+	// write to. Both keep their host paths inside the sandbox.
+	ReadOnly []string
+	Writable []string
+
+	Limits Limits
+}
+
+// Runner executes commands in some form of isolation
+type Runner interface {
+	// Name identifies the runner in reports
+	Name() string
+
+	// Available reports why the runner cannot be used on this host, or nil
+	Available() error
+
+	// Run executes the command and waits for it. A non-zero exit is returned
+	// as *exec.ExitError and an exceeded wall-clock limit as ErrTimeout.
+	Run(ctx context.Context, cmd Command, stdout, stderr io.Writer) error
+}
+
+// New returns the named runner: "bwrap", "docker" or "process". An empty
+// name picks bubblewrap and fails when it does not work on this host, since
+// the process runner does not isolate anything and must be chosen explicitly.
+func New(name string) (Runner, error) {
+	var r Runner
+	switch strings.ToLower(name) {
+	case "":
+		bw := &Bubblewrap{}
+		if err := bw.Available(); err != nil {
+			return nil, fmt.Errorf("bubblewrap is not available (%v); choose the docker sandbox, or the process sandbox to run without isolation", err)
+		}
+		return bw, nil
+	case "bwrap", "bubblewrap":
+		r = &Bubblewrap{}
+	case "docker":
+		r = &Docker{Image: DefaultImage}
+	case "process":
+		r = &Process{}
+	default:
+		return nil, fmt.Errorf("unknown sandbox %q (want bwrap, docker or process)", name)
+	}
+	if err := r.Available(); err != nil {
+		return nil, fmt.Errorf("sandbox %s is not available: %w", r.Name(), err)
+	}
+	return r, nil
+}
+
+// run starts an already built command under the wall-clock limit. onTimeout,
+// when set, cleans up anything the command left running.
+func run(ctx context.Context, l Limits, name string, args []string, dir string, env []string, stdout, stderr io.Writer, onTimeout func()) error {
+	if l.Wall > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Wall)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = 5 * time.Second // do not wait on pipes held open by orphaned children
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		if onTimeout != nil {
+			onTimeout()
+		}
+		return fmt.Errorf("%w after %s", ErrTimeout, l.Wall)
+	}
+	return err
+}
+
+// withRlimits prefixes args with a shell that applies the CPU time and memory
+// limits and then execs the command, so they bind every process it starts
+func withRlimits(l Limits, args []string) []string {
+	var script []string
+	if l.CPUTime > 0 {
+		script = append(script, fmt.Sprintf("ulimit -t %d", int64(l.CPUTime/time.Second)))
+	}
+	if l.Memory > 0 {
+		script = append(script, fmt.Sprintf("ulimit -d %d", l.Memory/1024))
+	}
+	if len(script) == 0 {
+		return args
+	}
+	script = append(script, `exec "$@"`)
+	return append([]string{"/bin/sh", "-c", strings.Join(script, "; "), "sh"}, args...)
+}
+
+// commandError adds what a failed probe printed to its error
+func commandError(err error, out []byte) error {
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return fmt.Errorf("%v: %s", err, msg)
+	}
+	return err
+}
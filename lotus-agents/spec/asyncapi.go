@@ -0,0 +1,256 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AsyncAPI is the subset of an AsyncAPI 3.0 document the pipeline emits
+type AsyncAPI struct {
+	AsyncAPI           string                     `yaml:"asyncapi"`
+	Info               Info                       `yaml:"info"`
+	DefaultContentType string                     `yaml:"defaultContentType"`
+	Channels           map[string]*AsyncChannel   `yaml:"channels"`
+	Operations         map[string]*AsyncOperation `yaml:"operations"`
+	Components         AsyncComponents            `yaml:"components"`
+}
+
+// AsyncChannel is a Kafka topic
+type AsyncChannel struct {
+	Address     string         `yaml:"address"`
+	Description string         `yaml:"description,omitempty"`
+	Messages    map[string]Ref `yaml:"messages"`
+}
+
+// AsyncOperation declares whether the service sends or receives on a channel
+type AsyncOperation struct {
+	Action   string `yaml:"action"`
+	Channel  Ref    `yaml:"channel"`
+	Messages []Ref  `yaml:"messages"`
+}
+
+// AsyncComponents holds reusable messages and payload schemas
+type AsyncComponents struct {
+	Messages map[string]*AsyncMessage `yaml:"messages"`
+	Schemas  map[string]*Schema       `yaml:"schemas"`
+}
+
+// AsyncMessage describes one event type on the wire
+type AsyncMessage struct {
+	Name        string `yaml:"name"`
+	Title       string `yaml:"title,omitempty"`
+	Summary     string `yaml:"summary,omitempty"`
+	ContentType string `yaml:"contentType"`
+	Payload     Ref    `yaml:"payload"`
+
+	// XVersion is the event schema version carried in the Version field
+	XVersion string `yaml:"x-version"`
+}
+
+// Ref is a JSON reference
+type Ref struct {
+	Ref string `yaml:"$ref"`
+}
+
+// BuildAsyncAPI builds an AsyncAPI 3.0 catalog for the given events. Structs
+// listed in support (embedded metadata, nested value types) become component
+// schemas but not messages.
+func BuildAsyncAPI(service string, events []*Event, support []*Event) *AsyncAPI {
+	doc := &AsyncAPI{
+		AsyncAPI:           "3.0.0",
+		Info:               Info{Title: service + " events", Version: "1.0.0"},
+		DefaultContentType: "application/json",
+		Channels:           map[string]*AsyncChannel{},
+		Operations:         map[string]*AsyncOperation{},
+		Components: AsyncComponents{
+			Messages: map[string]*AsyncMessage{},
+			Schemas:  map[string]*Schema{},
+		},
+	}
+
+	byName := map[string]*Event{}
+	for _, e := range append(append([]*Event(nil), support...), events...) {
+		byName[e.Name] = e
+	}
+	for _, e := range support {
+		doc.Components.Schemas[e.Name] = eventSchema(e, byName)
+	}
+
+	// One channel per topic, however many events share it
+	channelIDs := map[string]string{}
+	usedIDs := map[string]bool{}
+
+	for _, e := range events {
+		version := e.Version
+		if version == "" {
+			version = "1"
+		}
+		topic := e.Topic
+		if topic == "" {
+			topic = DefaultTopic(service, e.Name, version)
+		}
+		base := strings.TrimSuffix(e.Name, "Event")
+		channelID, ok := channelIDs[topic]
+		if !ok {
+			channelID = topicChannelID(topic)
+			for n := 2; usedIDs[channelID]; n++ {
+				channelID = fmt.Sprintf("%s%d", topicChannelID(topic), n)
+			}
+			channelIDs[topic] = channelID
+			usedIDs[channelID] = true
+		}
+		direction := e.Direction
+		if direction == "" {
+			direction = DirectionSend
+		}
+
+		doc.Components.Schemas[e.Name] = eventSchema(e, byName)
+		doc.Components.Messages[e.Name] = &AsyncMessage{
+			Name:        e.Name,
+			Title:       base,
+			Summary:     firstSentence(e.Doc),
+			ContentType: "application/json",
+			Payload:     Ref{"#/components/schemas/" + e.Name},
+			XVersion:    version,
+		}
+
+		ch, ok := doc.Channels[channelID]
+		if !ok {
+			ch = &AsyncChannel{Address: topic, Messages: map[string]Ref{}}
+			doc.Channels[channelID] = ch
+		}
+		ch.Messages[e.Name] = Ref{"#/components/messages/" + e.Name}
+
+		verb := "publish"
+		if direction == DirectionReceive {
+			verb = "consume"
+		}
+		doc.Operations[verb+base] = &AsyncOperation{
+			Action:   direction,
+			Channel:  Ref{"#/channels/" + channelID},
+			Messages: []Ref{{"#/channels/" + channelID + "/messages/" + e.Name}},
+		}
+	}
+	return doc
+}
+
+// YAML renders the document; map keys are emitted in sorted order so the
+// output is deterministic
+func (d *AsyncAPI) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(d); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DefaultTopic derives a topic name for events without an explicit
+// //asyncapi:topic directive, e.g. ("inventory", "StockReservedEvent", "1")
+// → "inventory.stock-reserved.v1"
+func DefaultTopic(service, eventName, version string) string {
+	return fmt.Sprintf("%s.%s.v%s", service, kebab(strings.TrimSuffix(eventName, "Event")), version)
+}
+
+func eventSchema(e *Event, byName map[string]*Event) *Schema {
+	s := &Schema{Type: SchemaType{"object"}, Properties: map[string]*Schema{}}
+	for _, f := range e.AllFields(byName) {
+		s.Properties[f.JSONName] = goTypeSchema(f.GoType, byName)
+		if !f.OmitEmpty && !strings.HasPrefix(f.GoType, "*") {
+			s.Required = append(s.Required, f.JSONName)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// goTypeSchema maps a Go type expression to a JSON Schema
+func goTypeSchema(goType string, byName map[string]*Event) *Schema {
+	t := strings.TrimPrefix(goType, "*")
+	switch {
+	case strings.HasPrefix(t, "[]") && t != "[]byte":
+		return &Schema{Type: SchemaType{"array"}, Items: goTypeSchema(t[2:], byName)}
+	case strings.HasPrefix(t, "map["):
+		return &Schema{Type: SchemaType{"object"}}
+	}
+	switch t {
+	case "string", "[]byte":
+		return &Schema{Type: SchemaType{"string"}}
+	case "bool":
+		return &Schema{Type: SchemaType{"boolean"}}
+	case "int64", "uint64":
+		return &Schema{Type: SchemaType{"integer"}, Format: "int64"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return &Schema{Type: SchemaType{"integer"}}
+	case "float32", "float64":
+		return &Schema{Type: SchemaType{"number"}}
+	case "time.Time":
+		return &Schema{Type: SchemaType{"string"}, Format: "date-time"}
+	case "uuid.UUID":
+		return &Schema{Type: SchemaType{"string"}, Format: "uuid"}
+	case "json.RawMessage", "any", "interface{}":
+		return &Schema{}
+	}
+	if _, ok := byName[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + t}
+	}
+	return &Schema{Type: SchemaType{"string"}}
+}
+
+func kebab(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			sb.WriteByte('-')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// topicChannelID derives a channel ID from a topic name, e.g.
+// "inventory.stock-reserved.v1" → "inventoryStockReservedV1"
+func topicChannelID(topic string) string {
+	parts := strings.FieldsFunc(topic, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	var sb strings.Builder
+	for i, p := range parts {
+		if i == 0 {
+			sb.WriteString(lowerFirst(p))
+			continue
+		}
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	if sb.Len() == 0 {
+		return "channel"
+	}
+	return sb.String()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func firstSentence(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if i := strings.Index(s, ". "); i >= 0 {
+		return s[:i+1]
+	}
+	return s
+}
@@ -0,0 +1,177 @@
+package spec
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+)
+
+// Event directions as used by AsyncAPI operations
+const (
+	DirectionSend    = "send"
+	DirectionReceive = "receive"
+)
+
+// Event is a domain event struct parsed from Go source. Topic, Direction and
+// Version come from //asyncapi: directives in the struct's doc comment:
+//
+//	// StockReservedEvent is published when stock is held for an order.
+//	//asyncapi:topic inventory.stock-reserved.v1
+//	//asyncapi:direction send
+//	//asyncapi:version 1
+type Event struct {
+	Name      string
+	Doc       string
+	Topic     string
+	Direction string
+	Version   string
+	File      string
+	Line      int
+	Fields    []EventField
+
+	// Embedded lists struct types embedded in this one, e.g. a shared metadata struct
+	Embedded []string
+}
+
+// EventField is a JSON-visible field of an event struct
+type EventField struct {
+	Name      string
+	JSONName  string
+	GoType    string
+	OmitEmpty bool
+	Line      int
+}
+
+// ParseEvents returns every struct type declared in a Go source file
+func ParseEvents(filename, src string) ([]*Event, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	var events []*Event
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, sp := range gen.Specs {
+			ts := sp.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			ev := &Event{Name: ts.Name.Name, File: filename, Line: fset.Position(ts.Pos()).Line}
+			applyEventDirectives(ev, doc)
+			for _, field := range st.Fields.List {
+				tag := ""
+				if field.Tag != nil {
+					tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+				}
+				if tag == "-" {
+					continue
+				}
+				jsonName, opts, _ := strings.Cut(tag, ",")
+				goType := types.ExprString(field.Type)
+				if len(field.Names) == 0 {
+					ev.Embedded = append(ev.Embedded, strings.TrimPrefix(goType, "*"))
+					continue
+				}
+				for _, n := range field.Names {
+					if !n.IsExported() {
+						continue
+					}
+					name := jsonName
+					if name == "" {
+						name = n.Name
+					}
+					ev.Fields = append(ev.Fields, EventField{
+						Name:      n.Name,
+						JSONName:  name,
+						GoType:    goType,
+						OmitEmpty: strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero"),
+						Line:      fset.Position(n.Pos()).Line,
+					})
+				}
+			}
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
+
+func applyEventDirectives(ev *Event, doc *ast.CommentGroup) {
+	if doc == nil {
+		return
+	}
+	for _, c := range doc.List {
+		directive, ok := strings.CutPrefix(c.Text, "//asyncapi:")
+		if !ok {
+			continue
+		}
+		key, val, _ := strings.Cut(directive, " ")
+		val = strings.TrimSpace(val)
+		switch key {
+		case "topic":
+			ev.Topic = val
+		case "direction":
+			switch strings.ToLower(val) {
+			case "send", "publish", "produce":
+				ev.Direction = DirectionSend
+			case "receive", "subscribe", "consume":
+				ev.Direction = DirectionReceive
+			}
+		case "version":
+			ev.Version = strings.TrimPrefix(val, "v")
+		}
+	}
+	ev.Doc = strings.TrimSpace(doc.Text())
+}
+
+// AllFields returns the event's fields including those promoted from
+// embedded structs found in byName
+func (e *Event) AllFields(byName map[string]*Event) []EventField {
+	return e.allFields(byName, 0)
+}
+
+func (e *Event) allFields(byName map[string]*Event, depth int) []EventField {
+	fields := append([]EventField(nil), e.Fields...)
+	if depth > 8 {
+		return fields
+	}
+	for _, name := range e.Embedded {
+		if inner, ok := byName[name[strings.LastIndex(name, ".")+1:]]; ok {
+			fields = append(fields, inner.allFields(byName, depth+1)...)
+		}
+	}
+	return fields
+}
+
+// ClassifyEvents splits parsed structs into events and supporting types.
+// A struct is an event when it carries an //asyncapi: directive, or when its
+// name ends in "Event" and no other struct embeds it (embedded structs such
+// as BaseEvent hold shared metadata rather than being events themselves).
+func ClassifyEvents(all []*Event) (events, support []*Event) {
+	embedded := map[string]bool{}
+	for _, e := range all {
+		for _, name := range e.Embedded {
+			embedded[name[strings.LastIndex(name, ".")+1:]] = true
+		}
+	}
+	for _, e := range all {
+		annotated := e.Topic != "" || e.Direction != "" || e.Version != ""
+		if annotated || strings.HasSuffix(e.Name, "Event") && !embedded[e.Name] {
+			events = append(events, e)
+		} else {
+			support = append(support, e)
+		}
+	}
+	return events, support
+}
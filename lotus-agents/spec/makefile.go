@@ -0,0 +1,145 @@
+package spec
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MakeTarget is an explicit rule target declared in a Makefile
+type MakeTarget struct {
+	Name   string
+	Line   int
+	Phony  bool
+	Recipe []string
+
+	// Prereqs are the prerequisites of the target's rules, order-only ones included
+	Prereqs []string
+}
+
+// Makefile is the set of explicit targets a Makefile declares
+type Makefile struct {
+	Targets []*MakeTarget
+
+	// Vars holds simple variable assignments (=, :=, ?=), used to expand recipes
+	Vars map[string]string
+}
+
+var makeVarRe = regexp.MustCompile(`\$[({]([A-Za-z_][A-Za-z0-9_]*)[)}]`)
+
+// ParseMakefile extracts explicit targets and their recipes. Pattern rules,
+// special targets such as .PHONY and variable assignments are not targets.
+func ParseMakefile(src string) *Makefile {
+	m := &Makefile{Vars: map[string]string{}}
+	byName := map[string]*MakeTarget{}
+	phony := map[string]bool{}
+	var current []*MakeTarget
+
+	for i, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			for _, t := range current {
+				t.Recipe = append(t.Recipe, strings.TrimSpace(line))
+			}
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		current = nil
+		if strings.HasPrefix(line, " ") {
+			continue
+		}
+		colon := strings.Index(line, ":")
+		if eq := strings.Index(line, "="); eq >= 0 && (colon < 0 || eq <= colon+1) {
+			name := strings.TrimSpace(strings.TrimRight(line[:eq], ":?+"))
+			if name != "" && !strings.ContainsAny(name, " \t") {
+				m.Vars[name] = strings.TrimSpace(line[eq+1:])
+			}
+			continue
+		}
+		if colon < 0 {
+			continue
+		}
+		prereqs, inline, hasInline := strings.Cut(strings.TrimPrefix(line[colon+1:], ":"), ";")
+		for _, name := range strings.Fields(line[:colon]) {
+			if name == ".PHONY" {
+				for _, p := range strings.Fields(prereqs) {
+					phony[p] = true
+				}
+				continue
+			}
+			if strings.HasPrefix(name, ".") || strings.ContainsAny(name, "%$") {
+				continue
+			}
+			t, ok := byName[name]
+			if !ok {
+				t = &MakeTarget{Name: name, Line: i + 1}
+				byName[name] = t
+				m.Targets = append(m.Targets, t)
+			}
+			for _, p := range strings.Fields(prereqs) {
+				if p != "|" {
+					t.Prereqs = append(t.Prereqs, p)
+				}
+			}
+			if hasInline {
+				t.Recipe = append(t.Recipe, strings.TrimSpace(inline))
+			}
+			current = append(current, t)
+		}
+	}
+
+	for _, t := range m.Targets {
+		t.Phony = phony[t.Name]
+	}
+	return m
+}
+
+// Target returns the named target, or nil when the Makefile does not declare it
+func (m *Makefile) Target(name string) *MakeTarget {
+	for _, t := range m.Targets {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Has reports whether the Makefile declares the target
+func (m *Makefile) Has(name string) bool {
+	for _, t := range m.Targets {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the declared target names in sorted order
+func (m *Makefile) Names() []string {
+	names := make([]string, 0, len(m.Targets))
+	for _, t := range m.Targets {
+		names = append(names, t.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand substitutes $(VAR) and ${VAR} references to variables the Makefile
+// assigns, leaving unknown references untouched
+func (m *Makefile) Expand(s string) string {
+	for range 8 {
+		expanded := makeVarRe.ReplaceAllStringFunc(s, func(ref string) string {
+			if v, ok := m.Vars[makeVarRe.FindStringSubmatch(ref)[1]]; ok {
+				return v
+			}
+			return ref
+		})
+		if expanded == s {
+			break
+		}
+		s = expanded
+	}
+	return s
+}
@@ -0,0 +1,270 @@
+// Package spec models the machine-readable contracts the pipeline produces
+// and consumes (OpenAPI today), independent of any agent.
+package spec
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPI is the subset of an OpenAPI 3.x document the pipeline relies on
+type OpenAPI struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Servers    []Server             `yaml:"servers,omitempty"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components,omitempty"`
+}
+
+// Info carries the document title and version
+type Info struct {
+	Title   string `yaml:"title"`
+	Version string `yaml:"version"`
+}
+
+// Server is a base URL the API is served from
+type Server struct {
+	URL string `yaml:"url"`
+}
+
+// Components holds reusable schemas and parameters
+type Components struct {
+	Schemas    map[string]*Schema    `yaml:"schemas,omitempty"`
+	Parameters map[string]*Parameter `yaml:"parameters,omitempty"`
+}
+
+// PathItem holds the operations available on a single path
+type PathItem struct {
+	Parameters []Parameter `yaml:"parameters,omitempty"`
+	Get        *Operation  `yaml:"get,omitempty"`
+	Put        *Operation  `yaml:"put,omitempty"`
+	Post       *Operation  `yaml:"post,omitempty"`
+	Delete     *Operation  `yaml:"delete,omitempty"`
+	Options    *Operation  `yaml:"options,omitempty"`
+	Head       *Operation  `yaml:"head,omitempty"`
+	Patch      *Operation  `yaml:"patch,omitempty"`
+	Trace      *Operation  `yaml:"trace,omitempty"`
+}
+
+// Operations returns the operations on the path keyed by upper-case HTTP method
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := map[string]*Operation{}
+	for method, op := range map[string]*Operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"OPTIONS": p.Options, "HEAD": p.Head, "PATCH": p.Patch, "TRACE": p.Trace,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// Operation is a single API operation
+type Operation struct {
+	OperationID string              `yaml:"operationId,omitempty"`
+	Summary     string              `yaml:"summary,omitempty"`
+	Description string              `yaml:"description,omitempty"`
+	Tags        []string            `yaml:"tags,omitempty"`
+	Parameters  []Parameter         `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody        `yaml:"requestBody,omitempty"`
+	Responses   map[string]Response `yaml:"responses"`
+
+	// XOperation names the ServiceDefinition operation this endpoint implements
+	XOperation string `yaml:"x-operation,omitempty"`
+}
+
+// Parameter is a path, query, header or cookie parameter
+type Parameter struct {
+	Ref      string  `yaml:"$ref,omitempty"`
+	Name     string  `yaml:"name"`
+	In       string  `yaml:"in"`
+	Required bool    `yaml:"required,omitempty"`
+	Schema   *Schema `yaml:"schema,omitempty"`
+}
+
+// RequestBody describes the payload an operation accepts
+type RequestBody struct {
+	Required bool                 `yaml:"required,omitempty"`
+	Content  map[string]MediaType `yaml:"content"`
+}
+
+// Response describes a single response of an operation
+type Response struct {
+	Description string               `yaml:"description"`
+	Content     map[string]MediaType `yaml:"content,omitempty"`
+}
+
+// MediaType binds a schema to a content type
+type MediaType struct {
+	Schema *Schema `yaml:"schema,omitempty"`
+}
+
+// Schema is the subset of JSON Schema used by OpenAPI 3.1 documents
+type Schema struct {
+	Ref        string             `yaml:"$ref,omitempty"`
+	Type       SchemaType         `yaml:"type,omitempty"`
+	Format     string             `yaml:"format,omitempty"`
+	Properties map[string]*Schema `yaml:"properties,omitempty"`
+	Required   []string           `yaml:"required,omitempty"`
+	Items      *Schema            `yaml:"items,omitempty"`
+	Enum       []any              `yaml:"enum,omitempty"`
+	AllOf      []*Schema          `yaml:"allOf,omitempty"`
+	OneOf      []*Schema          `yaml:"oneOf,omitempty"`
+	AnyOf      []*Schema          `yaml:"anyOf,omitempty"`
+}
+
+// SchemaType accepts both the 3.0 scalar form (type: string) and the
+// 3.1 array form (type: [string, "null"])
+type SchemaType []string
+
+func (t *SchemaType) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*t = SchemaType{node.Value}
+		return nil
+	case yaml.SequenceNode:
+		var types []string
+		if err := node.Decode(&types); err != nil {
+			return err
+		}
+		*t = types
+		return nil
+	}
+	return fmt.Errorf("line %d: schema type must be a string or list of strings", node.Line)
+}
+
+func (t SchemaType) MarshalYAML() (any, error) {
+	if len(t) == 1 {
+		return t[0], nil
+	}
+	return []string(t), nil
+}
+
+// Primary returns the first non-null type, or "" when none is declared
+func (t SchemaType) Primary() string {
+	for _, s := range t {
+		if s != "null" {
+			return s
+		}
+	}
+	return ""
+}
+
+// ParseOpenAPI decodes a YAML or JSON OpenAPI document
+func ParseOpenAPI(data []byte) (*OpenAPI, error) {
+	var doc OpenAPI
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse openapi: %w", err)
+	}
+	if doc.OpenAPI == "" {
+		return nil, fmt.Errorf("parse openapi: missing top-level \"openapi\" version field")
+	}
+	return &doc, nil
+}
+
+// Endpoint is a flattened (method, path, operation) triple
+type Endpoint struct {
+	Method    string
+	Path      string
+	Operation *Operation
+}
+
+// Endpoints returns every operation in the document sorted by path then method
+func (d *OpenAPI) Endpoints() []Endpoint {
+	var eps []Endpoint
+	for path, item := range d.Paths {
+		if item == nil {
+			continue
+		}
+		for method, op := range item.Operations() {
+			eps = append(eps, Endpoint{Method: method, Path: path, Operation: op})
+		}
+	}
+	sort.Slice(eps, func(i, j int) bool {
+		if eps[i].Path != eps[j].Path {
+			return eps[i].Path < eps[j].Path
+		}
+		return eps[i].Method < eps[j].Method
+	})
+	return eps
+}
+
+// BasePaths returns the URL path component of every declared server,
+// e.g. "https://api.example.com/v1" → "/v1"
+func (d *OpenAPI) BasePaths() []string {
+	var bases []string
+	for _, s := range d.Servers {
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			continue
+		}
+		if p := strings.TrimSuffix(u.Path, "/"); p != "" {
+			bases = append(bases, p)
+		}
+	}
+	return bases
+}
+
+// ResolveParameter follows a local "#/components/parameters/<name>" reference
+func (d *OpenAPI) ResolveParameter(p Parameter) (Parameter, bool) {
+	for depth := 0; p.Ref != "" && depth < 16; depth++ {
+		name, ok := strings.CutPrefix(p.Ref, "#/components/parameters/")
+		if !ok {
+			return Parameter{}, false
+		}
+		next, ok := d.Components.Parameters[name]
+		if !ok || next == nil {
+			return Parameter{}, false
+		}
+		p = *next
+	}
+	return p, p.Ref == "" && p.Name != ""
+}
+
+// Parameters returns the resolved parameters of an endpoint: those of its
+// path item, overridden by the operation's own with the same name and
+// location. References that do not resolve are left out.
+func (d *OpenAPI) Parameters(ep Endpoint) []Parameter {
+	var params []Parameter
+	index := map[string]int{}
+	var declared []Parameter
+	if item := d.Paths[ep.Path]; item != nil {
+		declared = append(declared, item.Parameters...)
+	}
+	declared = append(declared, ep.Operation.Parameters...)
+	for _, raw := range declared {
+		p, ok := d.ResolveParameter(raw)
+		if !ok {
+			continue
+		}
+		key := p.In + " " + p.Name
+		if i, dup := index[key]; dup {
+			params[i] = p
+			continue
+		}
+		index[key] = len(params)
+		params = append(params, p)
+	}
+	return params
+}
+
+// ResolveSchema follows a local "#/components/schemas/<name>" reference
+func (d *OpenAPI) ResolveSchema(s *Schema) (*Schema, bool) {
+	for depth := 0; s != nil && s.Ref != "" && depth < 16; depth++ {
+		name, ok := strings.CutPrefix(s.Ref, "#/components/schemas/")
+		if !ok {
+			return nil, false
+		}
+		next, ok := d.Components.Schemas[name]
+		if !ok {
+			return nil, false
+		}
+		s = next
+	}
+	return s, s != nil
+}
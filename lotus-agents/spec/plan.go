@@ -0,0 +1,179 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// Plan is the shared design the Planner agent produces before any code is
+// generated. Every downstream agent receives it verbatim and must use its
+// names; the plan conformance check reports generated identifiers outside it.
+type Plan struct {
+	UseCases  []PlanUseCase  `json:"use_cases"`
+	Ports     []PlanPort     `json:"ports"`
+	Tables    []PlanTable    `json:"tables"`
+	Events    []PlanEvent    `json:"events"`
+	Endpoints []PlanEndpoint `json:"endpoints"`
+}
+
+// PlanUseCase is one application use case and the business operation it implements
+type PlanUseCase struct {
+	Name      string `json:"name"`
+	Operation string `json:"operation"`
+}
+
+// PlanPort is an interface at a layer boundary: an input port a use case
+// implements, an output port it depends on, or a domain repository
+type PlanPort struct {
+	Name    string   `json:"name"`
+	Kind    string   `json:"kind"`
+	Methods []string `json:"methods"`
+}
+
+// PlanTable is a database table and the entity it stores
+type PlanTable struct {
+	Name   string `json:"name"`
+	Entity string `json:"entity"`
+}
+
+// PlanEvent is a domain event, its Kafka topic and whether the service publishes or consumes it
+type PlanEvent struct {
+	Name      string `json:"name"`
+	Topic     string `json:"topic"`
+	Direction string `json:"direction"`
+}
+
+// PlanEndpoint is an HTTP endpoint and the use case it calls
+type PlanEndpoint struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operation_id"`
+	UseCase     string `json:"use_case"`
+}
+
+// Port kinds accepted in PlanPort.Kind
+const (
+	PortInput      = "input"
+	PortOutput     = "output"
+	PortRepository = "repository"
+)
+
+var (
+	snakeCaseRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	topicRe     = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+)
+
+// ParsePlan decodes a plan document, rejecting unknown fields
+func ParsePlan(data []byte) (*Plan, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	p := &Plan{}
+	if err := dec.Decode(p); err != nil {
+		return nil, fmt.Errorf("parse plan: %w", err)
+	}
+	return p, nil
+}
+
+// Validate returns every structural problem with the plan: names that are
+// not valid identifiers in their target language, duplicates, unknown kinds
+// and endpoints that reference undeclared use cases
+func (p *Plan) Validate() []string {
+	var errs []string
+	fail := func(format string, args ...any) { errs = append(errs, fmt.Sprintf(format, args...)) }
+
+	if len(p.UseCases) == 0 {
+		fail("use_cases is empty")
+	}
+	useCases := map[string]bool{}
+	for i, uc := range p.UseCases {
+		if !isExportedIdent(uc.Name) {
+			fail("use_cases[%d].name %q is not an exported Go identifier", i, uc.Name)
+		}
+		if useCases[uc.Name] {
+			fail("use case %s is declared twice", uc.Name)
+		}
+		useCases[uc.Name] = true
+		if uc.Operation == "" {
+			fail("use case %s has no operation", uc.Name)
+		}
+	}
+
+	ports := map[string]bool{}
+	for i, port := range p.Ports {
+		if !isExportedIdent(port.Name) {
+			fail("ports[%d].name %q is not an exported Go identifier", i, port.Name)
+		}
+		if ports[port.Name] {
+			fail("port %s is declared twice", port.Name)
+		}
+		ports[port.Name] = true
+		switch port.Kind {
+		case PortInput, PortOutput, PortRepository:
+		default:
+			fail("port %s has kind %q, want %s, %s or %s", port.Name, port.Kind, PortInput, PortOutput, PortRepository)
+		}
+		for _, m := range port.Methods {
+			if !isExportedIdent(m) {
+				fail("port %s method %q is not an exported Go identifier", port.Name, m)
+			}
+		}
+	}
+
+	tables := map[string]bool{}
+	for i, t := range p.Tables {
+		if !snakeCaseRe.MatchString(t.Name) {
+			fail("tables[%d].name %q is not snake_case", i, t.Name)
+		}
+		if tables[t.Name] {
+			fail("table %s is declared twice", t.Name)
+		}
+		tables[t.Name] = true
+	}
+
+	events := map[string]bool{}
+	for i, e := range p.Events {
+		if !isExportedIdent(e.Name) {
+			fail("events[%d].name %q is not an exported Go identifier", i, e.Name)
+		}
+		if events[e.Name] {
+			fail("event %s is declared twice", e.Name)
+		}
+		events[e.Name] = true
+		if !topicRe.MatchString(e.Topic) {
+			fail("event %s topic %q is not a lower-case topic name", e.Name, e.Topic)
+		}
+		if e.Direction != DirectionSend && e.Direction != DirectionReceive {
+			fail("event %s direction %q, want %s or %s", e.Name, e.Direction, DirectionSend, DirectionReceive)
+		}
+	}
+
+	endpoints := map[string]bool{}
+	for i, ep := range p.Endpoints {
+		method := strings.ToUpper(ep.Method)
+		switch method {
+		case "GET", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			fail("endpoints[%d] has method %q", i, ep.Method)
+		}
+		if !strings.HasPrefix(ep.Path, "/") {
+			fail("endpoints[%d] path %q must start with /", i, ep.Path)
+		}
+		key := method + " " + ep.Path
+		if endpoints[key] {
+			fail("endpoint %s is declared twice", key)
+		}
+		endpoints[key] = true
+		if ep.UseCase != "" && !useCases[ep.UseCase] {
+			fail("endpoint %s calls undeclared use case %s", key, ep.UseCase)
+		}
+	}
+	return errs
+}
+
+func isExportedIdent(s string) bool {
+	return token.IsIdentifier(s) && token.IsExported(s)
+}
@@ -0,0 +1,738 @@
+package spec
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SQLStatement is one semicolon-terminated statement of a SQL file
+type SQLStatement struct {
+	Text string
+	Line int
+
+	tokens []sqlToken
+}
+
+// sqlToken is a lexical token; keywords and unquoted identifiers are lower-cased
+type sqlToken struct {
+	kind     byte // 'w' word, 'q' quoted identifier, 's' string, 'n' number, 'p' punctuation
+	text     string
+	pos, end int
+}
+
+// SplitSQL splits a SQL file into statements, skipping comments and keeping
+// semicolons inside strings, quoted identifiers and dollar-quoted bodies
+func SplitSQL(src string) []SQLStatement {
+	var stmts []SQLStatement
+	var cur []sqlToken
+	flush := func() {
+		if len(cur) > 0 {
+			text := src[cur[0].pos:cur[len(cur)-1].end]
+			stmts = append(stmts, SQLStatement{Text: text, Line: 1 + strings.Count(src[:cur[0].pos], "\n"), tokens: cur})
+		}
+		cur = nil
+	}
+	for _, t := range lexSQL(src) {
+		if t.kind == 'p' && t.text == ";" {
+			flush()
+			continue
+		}
+		cur = append(cur, t)
+	}
+	flush()
+	return stmts
+}
+
+func lexSQL(src string) []sqlToken {
+	var toks []sqlToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "--"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return toks
+			}
+			i += end + 4
+		case c == '\'' || (c == 'E' || c == 'e') && i+1 < len(src) && src[i+1] == '\'':
+			start := i
+			if c != '\'' {
+				i++
+			}
+			i = skipQuoted(src, i+1, '\'')
+			toks = append(toks, sqlToken{'s', src[start:i], start, i})
+		case c == '"' || c == '`':
+			start := i
+			i = skipQuoted(src, i+1, c)
+			toks = append(toks, sqlToken{'q', strings.Trim(src[start:i], string(c)), start, i})
+		case c == '$' && dollarTag(src[i:]) != "":
+			tag := dollarTag(src[i:])
+			start := i
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += len(tag) + end + len(tag)
+			}
+			toks = append(toks, sqlToken{'s', src[start:i], start, i})
+		case isSQLWordByte(c) && !(c >= '0' && c <= '9'):
+			start := i
+			for i < len(src) && (isSQLWordByte(src[i]) || src[i] == '$') {
+				i++
+			}
+			toks = append(toks, sqlToken{'w', strings.ToLower(src[start:i]), start, i})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' || src[i] == 'e' || src[i] == 'E') {
+				i++
+			}
+			toks = append(toks, sqlToken{'n', src[start:i], start, i})
+		case c == ':' && i+1 < len(src) && src[i+1] == ':':
+			toks = append(toks, sqlToken{'p', "::", i, i + 2})
+			i += 2
+		default:
+			toks = append(toks, sqlToken{'p', string(c), i, i + 1})
+			i++
+		}
+	}
+	return toks
+}
+
+// skipQuoted returns the offset just past the closing quote, treating a doubled quote as an escape
+func skipQuoted(src string, i int, quote byte) int {
+	for i < len(src) {
+		if src[i] == quote {
+			if i+1 < len(src) && src[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1
+		}
+		if src[i] == '\\' && quote == '\'' && i+1 < len(src) {
+			i++
+		}
+		i++
+	}
+	return i
+}
+
+// dollarTag returns the opening $tag$ of a dollar-quoted string at the start of s
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		if s[j] == '$' {
+			return s[:j+1]
+		}
+		if !isSQLWordByte(s[j]) {
+			return ""
+		}
+	}
+	return ""
+}
+
+func isSQLWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// SQLSchema is the set of tables a sequence of DDL statements leaves behind
+type SQLSchema struct {
+	Tables map[string]*SQLTable
+}
+
+// SQLTable is a table's columns and keys
+type SQLTable struct {
+	Name        string
+	Columns     []*SQLColumn
+	PrimaryKey  []string
+	ForeignKeys []SQLForeignKey
+}
+
+// SQLColumn is a column definition; HasDefault is also set for serial,
+// identity, generated and auto-increment columns
+type SQLColumn struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	HasDefault bool
+}
+
+// SQLForeignKey is a foreign key; RefColumns is empty when it references the primary key
+type SQLForeignKey struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// NewSQLSchema returns an empty schema
+func NewSQLSchema() *SQLSchema {
+	return &SQLSchema{Tables: map[string]*SQLTable{}}
+}
+
+// Column returns the named column, or nil
+func (t *SQLTable) Column(name string) *SQLColumn {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Apply updates the schema with a CREATE TABLE, ALTER TABLE or DROP TABLE
+// statement; other statements are ignored. It reports statements that
+// reference a table or column the schema does not have.
+func (s *SQLSchema) Apply(stmt SQLStatement) error {
+	p := &sqlParser{toks: stmt.tokens}
+	switch {
+	case p.accept("create"):
+		p.until("table", "index", "view", "function", "extension", "type", "trigger", "sequence", "schema")
+		if !p.accept("table") {
+			return nil
+		}
+		ifNotExists := p.accept("if", "not", "exists")
+		name := p.qualifiedName()
+		if !p.peek("(") {
+			return nil // CREATE TABLE ... AS / PARTITION OF
+		}
+		if _, ok := s.Tables[name]; ok && ifNotExists {
+			return nil
+		}
+		t := &SQLTable{Name: name}
+		for _, def := range p.group() {
+			t.addDefinition(def)
+		}
+		s.Tables[name] = t
+	case p.accept("alter", "table"):
+		ifExists := p.accept("if", "exists")
+		p.accept("only")
+		name := p.qualifiedName()
+		t, ok := s.Tables[name]
+		if !ok {
+			if ifExists {
+				return nil
+			}
+			return fmt.Errorf("ALTER TABLE %s: no such table", name)
+		}
+		for _, action := range splitTopLevel(p.rest()) {
+			if err := s.alter(t, &sqlParser{toks: action}); err != nil {
+				return fmt.Errorf("ALTER TABLE %s: %w", name, err)
+			}
+		}
+	case p.accept("drop", "table"):
+		ifExists := p.accept("if", "exists")
+		for _, part := range splitTopLevel(p.rest()) {
+			name := (&sqlParser{toks: part}).qualifiedName()
+			if _, ok := s.Tables[name]; !ok && !ifExists {
+				return fmt.Errorf("DROP TABLE %s: no such table", name)
+			}
+			delete(s.Tables, name)
+		}
+	}
+	return nil
+}
+
+func (s *SQLSchema) alter(t *SQLTable, p *sqlParser) error {
+	switch {
+	case p.accept("add"):
+		if p.peek("constraint") || p.peek("primary") || p.peek("foreign") || p.peek("unique") || p.peek("check") || p.peek("exclude") {
+			t.addDefinition(p.rest())
+			return nil
+		}
+		p.accept("column")
+		ifNotExists := p.accept("if", "not", "exists")
+		def := p.rest()
+		if len(def) > 0 && t.Column(def[0].text) != nil {
+			if ifNotExists {
+				return nil
+			}
+			return fmt.Errorf("column %s already exists", def[0].text)
+		}
+		t.addDefinition(def)
+	case p.accept("drop"):
+		if p.accept("constraint") {
+			return nil
+		}
+		p.accept("column")
+		ifExists := p.accept("if", "exists")
+		col := p.ident()
+		if t.Column(col) == nil {
+			if ifExists {
+				return nil
+			}
+			return fmt.Errorf("no such column %s", col)
+		}
+		t.dropColumn(col)
+	case p.accept("alter"):
+		p.accept("column")
+		name := p.ident()
+		col := t.Column(name)
+		if col == nil {
+			return fmt.Errorf("no such column %s", name)
+		}
+		switch {
+		case p.accept("set", "not", "null"):
+			col.NotNull = true
+		case p.accept("drop", "not", "null"):
+			col.NotNull = false
+		case p.accept("set", "default"):
+			col.HasDefault = true
+		case p.accept("drop", "default"):
+			col.HasDefault = false
+		case p.accept("set", "data", "type"), p.accept("type"):
+			col.Type = typeText(p.until("using", "collate"))
+		}
+	case p.accept("rename", "column"), p.accept("rename"):
+		if p.accept("to") {
+			return nil // table renames are not tracked
+		}
+		from := p.ident()
+		p.accept("to")
+		to := p.ident()
+		col := t.Column(from)
+		if col == nil {
+			return fmt.Errorf("no such column %s", from)
+		}
+		col.Name = to
+	}
+	return nil
+}
+
+// addDefinition adds a column definition or a table constraint
+func (t *SQLTable) addDefinition(def []sqlToken) {
+	p := &sqlParser{toks: def}
+	if p.accept("constraint") {
+		p.ident()
+	}
+	switch {
+	case p.accept("primary", "key"):
+		t.PrimaryKey = identList(p.group())
+		for _, name := range t.PrimaryKey {
+			if c := t.Column(name); c != nil {
+				c.NotNull = true
+			}
+		}
+		return
+	case p.accept("foreign", "key"):
+		fk := SQLForeignKey{Columns: identList(p.group())}
+		if p.accept("references") {
+			fk.RefTable = p.qualifiedName()
+			if p.peek("(") {
+				fk.RefColumns = identList(p.group())
+			}
+		}
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+		return
+	case p.peek("unique") || p.peek("check") || p.peek("exclude") || p.peek("like") || p.peek("index") || p.peek("key"):
+		return
+	}
+
+	col := &SQLColumn{Name: p.ident()}
+	col.Type = typeText(p.until("constraint", "not", "null", "default", "primary", "references", "unique", "check", "generated", "collate", "auto_increment"))
+	switch col.Type {
+	case "serial", "bigserial", "smallserial", "serial4", "serial8", "serial2":
+		col.HasDefault = true
+	}
+	for !p.done() {
+		switch {
+		case p.accept("not", "null"):
+			col.NotNull = true
+		case p.accept("primary", "key"):
+			col.NotNull = true
+			t.PrimaryKey = []string{col.Name}
+		case p.accept("default"), p.accept("generated"), p.accept("auto_increment"):
+			col.HasDefault = true
+		case p.accept("references"):
+			fk := SQLForeignKey{Columns: []string{col.Name}, RefTable: p.qualifiedName()}
+			if p.peek("(") {
+				fk.RefColumns = identList(p.group())
+			}
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		default:
+			p.skip()
+		}
+	}
+	t.Columns = append(t.Columns, col)
+}
+
+func (t *SQLTable) dropColumn(name string) {
+	for i, c := range t.Columns {
+		if c.Name == name {
+			t.Columns = append(t.Columns[:i], t.Columns[i+1:]...)
+			return
+		}
+	}
+}
+
+// SQLInsert is an INSERT statement; Rows holds the source text of each
+// VALUES tuple's expressions and is empty for INSERT ... SELECT
+type SQLInsert struct {
+	Table      string
+	Columns    []string
+	Rows       [][]string
+	OnConflict bool
+	Line       int
+}
+
+// ParseInsert returns the INSERT in stmt, or false if stmt is not one
+func ParseInsert(stmt SQLStatement) (*SQLInsert, bool) {
+	p := &sqlParser{toks: stmt.tokens}
+	if !p.accept("insert", "into") {
+		return nil, false
+	}
+	ins := &SQLInsert{Table: p.qualifiedName(), Line: stmt.Line}
+	if p.accept("as") {
+		p.ident()
+	}
+	if p.peek("(") {
+		ins.Columns = identList(p.group())
+	}
+	if p.accept("values") {
+		for p.peek("(") {
+			var row []string
+			for _, expr := range p.group() {
+				row = append(row, stmt.source(expr))
+			}
+			ins.Rows = append(ins.Rows, row)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	for !p.done() {
+		if p.accept("on", "conflict") || p.accept("on", "duplicate") {
+			ins.OnConflict = true
+			break
+		}
+		p.skip()
+	}
+	return ins, true
+}
+
+// source returns the statement text the tokens span
+func (s SQLStatement) source(toks []sqlToken) string {
+	if len(toks) == 0 {
+		return ""
+	}
+	base := s.tokens[0].pos
+	return s.Text[toks[0].pos-base : toks[len(toks)-1].end-base]
+}
+
+// sqlParser walks the tokens of one statement or clause
+type sqlParser struct {
+	toks []sqlToken
+	i    int
+}
+
+func (p *sqlParser) done() bool { return p.i >= len(p.toks) }
+
+// peek reports whether the next token is the given word or punctuation
+func (p *sqlParser) peek(text string) bool {
+	return !p.done() && p.toks[p.i].kind != 'q' && p.toks[p.i].kind != 's' && p.toks[p.i].text == text
+}
+
+// accept consumes the given sequence of words if it comes next
+func (p *sqlParser) accept(words ...string) bool {
+	for j, w := range words {
+		k := p.i + j
+		if k >= len(p.toks) || p.toks[k].kind == 'q' || p.toks[k].kind == 's' || p.toks[k].text != w {
+			return false
+		}
+	}
+	p.i += len(words)
+	return true
+}
+
+// skip consumes one token, or a whole parenthesised group
+func (p *sqlParser) skip() {
+	if p.peek("(") {
+		p.group()
+		return
+	}
+	p.i++
+}
+
+func (p *sqlParser) ident() string {
+	if p.done() {
+		return ""
+	}
+	t := p.toks[p.i]
+	p.i++
+	return t.text
+}
+
+// qualifiedName reads a possibly schema-qualified name, dropping the public schema
+func (p *sqlParser) qualifiedName() string {
+	name := p.ident()
+	for p.accept(".") {
+		name += "." + p.ident()
+	}
+	return strings.TrimPrefix(name, "public.")
+}
+
+// group consumes a parenthesised list and returns its top-level comma-separated items
+func (p *sqlParser) group() [][]sqlToken {
+	if !p.accept("(") {
+		return nil
+	}
+	start, depth := p.i, 1
+	for ; p.i < len(p.toks); p.i++ {
+		if p.toks[p.i].kind != 'p' {
+			continue
+		}
+		switch p.toks[p.i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		if depth == 0 {
+			inner := p.toks[start:p.i]
+			p.i++
+			return splitTopLevel(inner)
+		}
+	}
+	return splitTopLevel(p.toks[start:])
+}
+
+// until consumes tokens up to the first top-level occurrence of any of the given words
+func (p *sqlParser) until(words ...string) []sqlToken {
+	start := p.i
+	for !p.done() {
+		for _, w := range words {
+			if p.peek(w) {
+				return p.toks[start:p.i]
+			}
+		}
+		p.skip()
+	}
+	return p.toks[start:]
+}
+
+func (p *sqlParser) rest() []sqlToken {
+	r := p.toks[p.i:]
+	p.i = len(p.toks)
+	return r
+}
+
+// splitTopLevel splits tokens at commas outside parentheses
+func splitTopLevel(toks []sqlToken) [][]sqlToken {
+	var parts [][]sqlToken
+	depth, start := 0, 0
+	for i, t := range toks {
+		if t.kind != 'p' {
+			continue
+		}
+		switch t.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, toks[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(toks) {
+		parts = append(parts, toks[start:])
+	}
+	return parts
+}
+
+func identList(items [][]sqlToken) []string {
+	var names []string
+	for _, item := range items {
+		if len(item) > 0 {
+			names = append(names, item[0].text)
+		}
+	}
+	return names
+}
+
+// typeText renders a column type, e.g. "varchar(255)", "numeric(10,2)", "timestamp with time zone"
+func typeText(toks []sqlToken) string {
+	var sb strings.Builder
+	for i, t := range toks {
+		if i > 0 && t.kind != 'p' && toks[i-1].kind != 'p' {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(t.text)
+	}
+	return sb.String()
+}
+
+// Clone returns a deep copy of the schema
+func (s *SQLSchema) Clone() *SQLSchema {
+	c := NewSQLSchema()
+	for name, t := range s.Tables {
+		ct := &SQLTable{Name: t.Name, PrimaryKey: append([]string(nil), t.PrimaryKey...)}
+		for _, col := range t.Columns {
+			cc := *col
+			ct.Columns = append(ct.Columns, &cc)
+		}
+		for _, fk := range t.ForeignKeys {
+			ct.ForeignKeys = append(ct.ForeignKeys, SQLForeignKey{
+				Columns:    append([]string(nil), fk.Columns...),
+				RefTable:   fk.RefTable,
+				RefColumns: append([]string(nil), fk.RefColumns...),
+			})
+		}
+		c.Tables[name] = ct
+	}
+	return c
+}
+
+// Diff describes how other differs from s in tables, columns, column types
+// and nullability, sorted; column order is ignored
+func (s *SQLSchema) Diff(other *SQLSchema) []string {
+	var diffs []string
+	for name := range s.Tables {
+		if _, ok := other.Tables[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("table %s is missing", name))
+		}
+	}
+	for name, ot := range other.Tables {
+		t, ok := s.Tables[name]
+		if !ok {
+			diffs = append(diffs, fmt.Sprintf("table %s is extra", name))
+			continue
+		}
+		for _, c := range t.Columns {
+			oc := ot.Column(c.Name)
+			switch {
+			case oc == nil:
+				diffs = append(diffs, fmt.Sprintf("column %s.%s is missing", name, c.Name))
+			case oc.Type != c.Type:
+				diffs = append(diffs, fmt.Sprintf("column %s.%s is %s, not %s", name, c.Name, oc.Type, c.Type))
+			case oc.NotNull != c.NotNull:
+				diffs = append(diffs, fmt.Sprintf("column %s.%s is %s, not %s", name, c.Name, nullability(oc.NotNull), nullability(c.NotNull)))
+			}
+		}
+		for _, oc := range ot.Columns {
+			if t.Column(oc.Name) == nil {
+				diffs = append(diffs, fmt.Sprintf("column %s.%s is extra", name, oc.Name))
+			}
+		}
+	}
+	sort.Strings(diffs)
+	return diffs
+}
+
+func nullability(notNull bool) string {
+	if notNull {
+		return "NOT NULL"
+	}
+	return "nullable"
+}
+
+// SQLIndex is a CREATE INDEX statement
+type SQLIndex struct {
+	Name         string
+	Table        string
+	Unique       bool
+	Concurrently bool
+}
+
+// ParseCreateIndex returns the CREATE INDEX in stmt, or false if stmt is not one
+func ParseCreateIndex(stmt SQLStatement) (*SQLIndex, bool) {
+	p := &sqlParser{toks: stmt.tokens}
+	if !p.accept("create") {
+		return nil, false
+	}
+	idx := &SQLIndex{Unique: p.accept("unique")}
+	if !p.accept("index") {
+		return nil, false
+	}
+	idx.Concurrently = p.accept("concurrently")
+	p.accept("if", "not", "exists")
+	if !p.peek("on") {
+		idx.Name = p.qualifiedName()
+	}
+	if !p.accept("on") {
+		return nil, false
+	}
+	p.accept("only")
+	idx.Table = p.qualifiedName()
+	return idx, true
+}
+
+// sqlKeywords are upper-cased by FormatSQL; type names and identifiers keep their case
+var sqlKeywords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`add all alter and as asc begin between by cascade case check
+		collate column comment commit concurrently conflict constraint create cross current_date
+		current_timestamp default deferrable deferred delete desc distinct do drop else end
+		exists extension false for foreign from full function group having if ilike in index
+		initially inner insert intersect into is join key left like limit not nothing null
+		offset on or order outer primary references rename replace restrict returning right
+		rollback schema select sequence set table then to transaction trigger true union unique
+		update using values view when where with without`) {
+		sqlKeywords[w] = true
+	}
+}
+
+// FormatSQL upper-cases keywords, trims trailing whitespace, collapses runs
+// of blank lines and terminates the last statement, leaving strings,
+// quoted identifiers, dollar-quoted bodies and comments untouched
+func FormatSQL(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	toks := lexSQL(src)
+	out := []byte(src)
+	inString := make([]bool, len(src)+1)
+	for i, t := range toks {
+		switch t.kind {
+		case 's', 'q':
+			for j := t.pos; j < t.end; j++ {
+				inString[j] = true
+			}
+		case 'w':
+			qualified := i > 0 && toks[i-1].text == "." && toks[i-1].end == t.pos ||
+				i+1 < len(toks) && toks[i+1].text == "." && toks[i+1].pos == t.end
+			if sqlKeywords[t.text] && !qualified {
+				copy(out[t.pos:t.end], strings.ToUpper(t.text))
+			}
+		}
+	}
+	if n := len(toks); n > 0 && toks[n-1].text != ";" {
+		end := toks[n-1].end
+		out = append(out[:end:end], append([]byte(";"), out[end:]...)...)
+		inString = append(inString[:end:end], append([]bool{false}, inString[end:]...)...)
+	}
+
+	var sb strings.Builder
+	blank := 0
+	for start := 0; start < len(out); {
+		end := bytes.IndexByte(out[start:], '\n')
+		if end < 0 {
+			end = len(out)
+		} else {
+			end += start
+		}
+		line := string(out[start:end])
+		if !inString[end] {
+			line = strings.TrimRight(line, " \t")
+		}
+		switch {
+		case line == "" && !inString[start]:
+			blank++
+		default:
+			if blank > 0 && sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			blank = 0
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		start = end + 1
+	}
+	return sb.String()
+}
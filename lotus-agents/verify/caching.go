@@ -0,0 +1,211 @@
+package verify
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+// Packages the cache layer is expected to use
+const (
+	singleflightImport    = "golang.org/x/sync/singleflight"
+	redisContainerImport  = "github.com/testcontainers/testcontainers-go/modules/redis"
+	domainEventImportPart = "/internal/domain/event"
+)
+
+// CheckCaching verifies the Redis cache decorators: each asserts the domain
+// repository interface it implements and declares all of its methods, loads
+// are protected against stampedes, TTLs are configurable, keys are
+// invalidated on domain events, and a Redis testcontainer test exists
+func CheckCaching(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Caching"}
+
+	interfaces := map[string][]string{}
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, repositoryDir) || !strings.HasSuffix(a.Filename, ".go") || strings.HasSuffix(a.Filename, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), a.Filename, a.Content, 0)
+		if err != nil {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			if it, ok := ts.Type.(*ast.InterfaceType); ok {
+				var methods []string
+				for _, m := range it.Methods.List {
+					for _, name := range m.Names {
+						methods = append(methods, name.Name)
+					}
+				}
+				interfaces[ts.Name.Name] = methods
+			}
+			return false
+		})
+	}
+
+	type assertion struct {
+		iface, typ, file string
+		line             int
+	}
+	var assertions []assertion
+	methods := map[string]map[string]bool{}
+	imports := map[string]bool{}
+	sawCode, sawTTL, sawLock, sawContainerTest := false, false, false, false
+
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, agents.CacheDir) || !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, a.Filename, a.Content, 0)
+		if err != nil {
+			report.add(SeverityError, a.Filename, 0, "cannot parse: %v", err)
+			continue
+		}
+		isTest := strings.HasSuffix(a.Filename, "_test.go")
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			if isTest {
+				sawContainerTest = sawContainerTest || path == redisContainerImport
+				continue
+			}
+			imports[path] = true
+		}
+		if isTest {
+			continue
+		}
+		sawCode = true
+		sawTTL = sawTTL || strings.Contains(a.Content, "TTL")
+		sawLock = sawLock || strings.Contains(a.Content, "SetNX")
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					continue
+				}
+				recv := receiverName(d.Recv.List[0].Type)
+				if methods[recv] == nil {
+					methods[recv] = map[string]bool{}
+				}
+				methods[recv][d.Name.Name] = true
+			case *ast.GenDecl:
+				if d.Tok != token.VAR {
+					continue
+				}
+				for _, sp := range d.Specs {
+					vs := sp.(*ast.ValueSpec)
+					if len(vs.Names) != 1 || vs.Names[0].Name != "_" || len(vs.Values) != 1 {
+						continue
+					}
+					iface := typeName(vs.Type)
+					typ := assertedType(vs.Values[0])
+					if iface != "" && typ != "" {
+						assertions = append(assertions, assertion{iface, typ, a.Filename, fset.Position(vs.Pos()).Line})
+					}
+				}
+			}
+		}
+	}
+
+	if !sawCode {
+		report.add(SeverityError, agents.CacheDir, 0, "no cache code was generated")
+		return report
+	}
+
+	decorated := map[string]bool{}
+	for _, as := range assertions {
+		want, ok := interfaces[as.iface]
+		if !ok {
+			continue
+		}
+		decorated[as.iface] = true
+		for _, m := range want {
+			if !methods[as.typ][m] {
+				report.add(SeverityError, as.file, as.line, "%s does not implement %s.%s", as.typ, as.iface, m)
+			}
+		}
+	}
+	if len(decorated) == 0 {
+		report.add(SeverityError, agents.CacheDir, 0, "no decorator asserts that it implements a domain repository interface (var _ repository.X = (*Y)(nil))")
+	}
+	var undecorated []string
+	for iface := range interfaces {
+		if !decorated[iface] {
+			undecorated = append(undecorated, iface)
+		}
+	}
+	sort.Strings(undecorated)
+	for _, iface := range undecorated {
+		report.add(SeverityInfo, agents.CacheDir, 0, "repository %s is not cached", iface)
+	}
+
+	if !imports[singleflightImport] && !sawLock {
+		report.add(SeverityWarning, agents.CacheDir, 0, "no stampede protection (%s or a SetNX lock)", singleflightImport)
+	}
+	if !sawTTL {
+		report.add(SeverityWarning, agents.CacheDir, 0, "no configurable TTL")
+	}
+	invalidates := false
+	for path := range imports {
+		invalidates = invalidates || strings.HasSuffix(path, domainEventImportPart)
+	}
+	if !invalidates {
+		report.add(SeverityWarning, agents.CacheDir, 0, "no cache code handles domain events for invalidation")
+	}
+	if !sawContainerTest {
+		report.add(SeverityWarning, agents.CacheDir, 0, "no integration test uses the Redis testcontainer (%s)", redisContainerImport)
+	}
+	return report
+}
+
+// receiverName returns T for receivers of type T or *T
+func receiverName(e ast.Expr) string {
+	if star, ok := e.(*ast.StarExpr); ok {
+		e = star.X
+	}
+	if idx, ok := e.(*ast.IndexExpr); ok {
+		e = idx.X
+	}
+	if id, ok := e.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+// typeName returns the unqualified name of a (possibly package-qualified) type
+func typeName(e ast.Expr) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+// assertedType returns T from (*T)(nil), &T{} or T{}
+func assertedType(e ast.Expr) string {
+	switch v := e.(type) {
+	case *ast.CallExpr:
+		if p, ok := v.Fun.(*ast.ParenExpr); ok {
+			if star, ok := p.X.(*ast.StarExpr); ok {
+				return receiverName(star.X)
+			}
+		}
+	case *ast.UnaryExpr:
+		return assertedType(v.X)
+	case *ast.CompositeLit:
+		return receiverName(v.Type)
+	}
+	return ""
+}
@@ -0,0 +1,301 @@
+package verify
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ciJob is the part of a GitHub Actions or GitLab CI job the check reads
+type ciJob struct {
+	name     string
+	commands []string
+	uses     []string
+	images   []string
+}
+
+// ciStep is a stage the CI definition must cover, detected in a job's
+// commands after make targets are expanded to their recipes
+type ciStep struct {
+	name    string
+	matches []string
+}
+
+var requiredCISteps = []ciStep{
+	{"lint", []string{"golangci-lint", "go vet", "staticcheck", "make lint"}},
+	{"unit tests", []string{"go test", "gotestsum"}},
+	{"race tests", []string{"-race"}},
+	{"integration tests", []string{"integration"}},
+	{"migration check", []string{"migrat"}},
+	{"image build", []string{"docker build", "docker buildx", "build-push-action", "kaniko", "buildah"}},
+}
+
+var (
+	makeCallRe = regexp.MustCompile(`(?:^|[;&|(@+-]|\s)(?:make|\$\(MAKE\)|\$\{MAKE\})\s+([^;&|\n]+)`)
+
+	// makeValueOptions are the make options whose value may be the next argument
+	makeValueOptions = map[string]bool{
+		"-C": true, "-f": true, "-I": true, "-o": true, "-W": true,
+		"--directory": true, "--file": true, "--makefile": true, "--include-dir": true,
+		"--old-file": true, "--assume-old": true, "--new-file": true, "--assume-new": true, "--what-if": true,
+	}
+
+	// gitlabReserved are top-level .gitlab-ci.yml keys that are not jobs
+	gitlabReserved = map[string]bool{
+		"stages": true, "variables": true, "default": true, "include": true, "workflow": true,
+		"image": true, "services": true, "cache": true, "before_script": true, "after_script": true,
+	}
+)
+
+// CheckCIPipeline verifies that the CI definition for the selected provider
+// calls only Makefile targets that exist, covers lint, unit, race and
+// integration tests, a migration check and an image build, runs service
+// containers for the declared datastores and pins the actions it uses.
+func CheckCIPipeline(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "CI pipeline"}
+
+	ciPath := agents.GitHubWorkflowPath
+	if svc.CI() == config.CIGitLab {
+		ciPath = agents.GitLabCIPath
+	}
+	art, ok := findArtifact(artifacts, ciPath)
+	if !ok {
+		report.add(SeverityError, ciPath, 0, "no CI definition was generated")
+		return report
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(art.Content), &doc); err != nil {
+		report.add(SeverityError, art.Filename, 0, "cannot parse CI definition: %v", err)
+		return report
+	}
+	var jobs []ciJob
+	if svc.CI() == config.CIGitLab {
+		jobs = gitlabJobs(doc)
+	} else {
+		jobs = githubJobs(doc)
+	}
+	if len(jobs) == 0 {
+		report.add(SeverityError, art.Filename, 0, "CI definition declares no jobs")
+		return report
+	}
+
+	var mk *spec.Makefile
+	for _, a := range artifacts {
+		if path.Base(a.Filename) == "Makefile" {
+			mk = spec.ParseMakefile(a.Content)
+			break
+		}
+	}
+
+	var coverage strings.Builder
+	unknown := map[string]string{}
+	for _, job := range jobs {
+		for _, cmd := range job.commands {
+			coverage.WriteString(cmd + "\n")
+			for _, target := range makeTargets(cmd) {
+				if mk == nil || mk.Target(target) == nil {
+					unknown[target] = job.name
+					continue
+				}
+				targetCommands(&coverage, mk, target, map[string]bool{})
+			}
+		}
+		for _, u := range job.uses {
+			coverage.WriteString(u + "\n")
+		}
+	}
+	targets := make([]string, 0, len(unknown))
+	for t := range unknown {
+		targets = append(targets, t)
+	}
+	sort.Strings(targets)
+	for _, t := range targets {
+		if mk == nil {
+			report.add(SeverityError, art.Filename, 0, "job %s runs make %s but no Makefile was generated", unknown[t], t)
+		} else {
+			report.add(SeverityError, art.Filename, 0, "job %s runs make %s, which the Makefile does not declare", unknown[t], t)
+		}
+	}
+
+	covered := strings.ToLower(coverage.String())
+	for _, step := range requiredCISteps {
+		found := false
+		for _, m := range step.matches {
+			if strings.Contains(covered, m) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report.add(SeverityError, art.Filename, 0, "no job runs %s", step.name)
+		}
+	}
+
+	for _, kind := range svc.InfrastructureKinds() {
+		found := false
+		for _, job := range jobs {
+			for _, img := range job.images {
+				if imageMatches(img, composeImageKeywords[kind]) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			report.add(SeverityWarning, art.Filename, 0, "no job runs a %s service container", kind)
+		}
+	}
+
+	for _, job := range jobs {
+		for _, u := range job.uses {
+			if strings.HasPrefix(u, "./") || strings.HasPrefix(u, "docker://") {
+				continue
+			}
+			_, ref, ok := strings.Cut(u, "@")
+			if !ok || ref == "main" || ref == "master" {
+				report.add(SeverityWarning, art.Filename, 0, "job %s uses %s without a pinned version", job.name, u)
+			}
+		}
+		for _, img := range job.images {
+			if strings.HasSuffix(img, ":latest") {
+				report.add(SeverityWarning, art.Filename, 0, "job %s uses unpinned image %s", job.name, img)
+			}
+		}
+	}
+	return report
+}
+
+// makeTargets returns the targets named by make or $(MAKE) invocations in a
+// shell command, skipping options, their values and variable assignments
+func makeTargets(cmd string) []string {
+	var targets []string
+	for _, m := range makeCallRe.FindAllStringSubmatch(cmd, -1) {
+		fields := strings.Fields(m[1])
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+			switch {
+			case makeValueOptions[f]:
+				i++
+			case (f == "-j" || f == "-l") && i+1 < len(fields) && isNumber(fields[i+1]):
+				i++
+			case strings.HasPrefix(f, "-") || strings.Contains(f, "=") || strings.HasPrefix(f, "$"):
+			default:
+				targets = append(targets, f)
+			}
+		}
+	}
+	return targets
+}
+
+// targetCommands writes the expanded recipe of a target to sb, followed by
+// those of its prerequisites and of the targets its recipe runs through make
+func targetCommands(sb *strings.Builder, mk *spec.Makefile, name string, seen map[string]bool) {
+	t := mk.Target(name)
+	if t == nil || seen[name] {
+		return
+	}
+	seen[name] = true
+	recipe := mk.Expand(strings.Join(t.Recipe, "\n"))
+	sb.WriteString(recipe + "\n")
+	for _, p := range t.Prereqs {
+		targetCommands(sb, mk, mk.Expand(p), seen)
+	}
+	for _, line := range strings.Split(recipe, "\n") {
+		for _, sub := range makeTargets(line) {
+			targetCommands(sb, mk, sub, seen)
+		}
+	}
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+func githubJobs(doc map[string]any) []ciJob {
+	jobsMap := stringMapAny(doc["jobs"])
+	names := sortedKeys(jobsMap)
+	var jobs []ciJob
+	for _, name := range names {
+		j := ciJob{name: name}
+		steps, _ := get(jobsMap[name], "steps").([]any)
+		for _, s := range steps {
+			if run := str(s, "run"); run != "" {
+				j.commands = append(j.commands, run)
+			}
+			if uses := str(s, "uses"); uses != "" {
+				j.uses = append(j.uses, uses)
+			}
+		}
+		if img := str(jobsMap[name], "container", "image"); img != "" {
+			j.images = append(j.images, img)
+		} else if img := str(jobsMap[name], "container"); img != "" {
+			j.images = append(j.images, img)
+		}
+		services := stringMapAny(get(jobsMap[name], "services"))
+		for _, svcName := range sortedKeys(services) {
+			if img := str(services[svcName], "image"); img != "" {
+				j.images = append(j.images, img)
+			}
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+func gitlabJobs(doc map[string]any) []ciJob {
+	var defaultScripts []string
+	for _, key := range []string{"before_script", "after_script"} {
+		defaultScripts = append(defaultScripts, stringSlice(doc[key])...)
+		defaultScripts = append(defaultScripts, stringSlice(get(doc, "default", key))...)
+	}
+	var jobs []ciJob
+	for _, name := range sortedKeys(doc) {
+		body, ok := doc[name].(map[string]any)
+		if gitlabReserved[name] || strings.HasPrefix(name, ".") || !ok {
+			continue
+		}
+		j := ciJob{name: name, commands: append([]string(nil), defaultScripts...)}
+		for _, key := range []string{"before_script", "script", "after_script"} {
+			j.commands = append(j.commands, stringSlice(body[key])...)
+		}
+		if img := gitlabImage(body["image"]); img != "" {
+			j.images = append(j.images, img)
+		}
+		services, _ := body["services"].([]any)
+		if len(services) == 0 {
+			services, _ = doc["services"].([]any)
+		}
+		for _, s := range services {
+			if img := gitlabImage(s); img != "" {
+				j.images = append(j.images, img)
+			}
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs
+}
+
+// gitlabImage reads an image given as a string or as {name: ...}
+func gitlabImage(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return str(v, "name")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+package verify
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// composeFile is the subset of the Compose specification the check reads
+type composeFile struct {
+	Services map[string]*composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image       string    `yaml:"image"`
+	Build       yaml.Node `yaml:"build"`
+	DependsOn   yaml.Node `yaml:"depends_on"`
+	Healthcheck *struct {
+		Test yaml.Node `yaml:"test"`
+	} `yaml:"healthcheck"`
+	Profiles []string `yaml:"profiles"`
+}
+
+// dependencies returns depends_on as service → condition ("" for the short list form)
+func (s *composeService) dependencies() map[string]string {
+	deps := map[string]string{}
+	switch s.DependsOn.Kind {
+	case yaml.SequenceNode:
+		var names []string
+		_ = s.DependsOn.Decode(&names)
+		for _, n := range names {
+			deps[n] = ""
+		}
+	case yaml.MappingNode:
+		var m map[string]struct {
+			Condition string `yaml:"condition"`
+		}
+		_ = s.DependsOn.Decode(&m)
+		for n, v := range m {
+			deps[n] = v.Condition
+		}
+	}
+	return deps
+}
+
+// composeImageKeywords identifies the container image serving each infrastructure kind
+var composeImageKeywords = map[string][]string{
+	config.KindPostgres:      {"postgres", "postgis"},
+	config.KindMySQL:         {"mysql", "mariadb"},
+	config.KindMongoDB:       {"mongo"},
+	config.KindRedis:         {"redis", "valkey"},
+	config.KindKafka:         {"kafka", "redpanda"},
+	config.KindRabbitMQ:      {"rabbitmq"},
+	config.KindElasticsearch: {"elasticsearch", "opensearch"},
+}
+
+var composeVarRe = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:?[-?][^}]*)?\}`)
+
+// CheckComposeStack verifies that docker-compose.yml runs the service with
+// every datastore and broker declared in the definition, that they have
+// healthchecks and gate startup, that migrations run in an init container,
+// and that every variable the file reads is documented in .env.example.
+func CheckComposeStack(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Compose stack"}
+
+	var art agents.Artifact
+	found := false
+	for _, a := range artifacts {
+		switch path.Base(a.Filename) {
+		case "docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml":
+			art, found = a, true
+		}
+	}
+	if !found {
+		report.add(SeverityError, "docker-compose.yml", 0, "no compose file was generated")
+		return report
+	}
+	var cf composeFile
+	if err := yaml.Unmarshal([]byte(art.Content), &cf); err != nil {
+		report.add(SeverityError, art.Filename, 0, "cannot parse compose file: %v", err)
+		return report
+	}
+
+	names := make([]string, 0, len(cf.Services))
+	for name := range cf.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var app string
+	for _, name := range names {
+		if cf.Services[name].Build.Kind != 0 && !strings.Contains(name, "migrat") && !strings.Contains(name, "seed") {
+			app = name
+			break
+		}
+	}
+	if app == "" {
+		report.add(SeverityError, art.Filename, 0, "no service builds the application image (missing build:)")
+		return report
+	}
+	appDeps := cf.Services[app].dependencies()
+
+	for _, name := range names {
+		s := cf.Services[name]
+		if s.Image != "" && (strings.HasSuffix(s.Image, ":latest") || !strings.Contains(path.Base(s.Image), ":")) {
+			report.add(SeverityWarning, art.Filename, 0, "service %s uses unpinned image %s", name, s.Image)
+		}
+	}
+
+	for _, kind := range svc.InfrastructureKinds() {
+		backing := ""
+		for _, name := range names {
+			if imageMatches(cf.Services[name].Image, composeImageKeywords[kind]) {
+				backing = name
+				break
+			}
+		}
+		if backing == "" {
+			report.add(SeverityError, art.Filename, 0, "declared integration %s has no service in the compose stack", kind)
+			continue
+		}
+		if cf.Services[backing].Healthcheck == nil {
+			report.add(SeverityError, art.Filename, 0, "service %s (%s) has no healthcheck", backing, kind)
+		}
+		if cond, ok := appDeps[backing]; !ok {
+			report.add(SeverityError, art.Filename, 0, "service %s does not depend on %s", app, backing)
+		} else if cond != "service_healthy" {
+			report.add(SeverityWarning, art.Filename, 0, "service %s should wait for %s with condition: service_healthy", app, backing)
+		}
+	}
+
+	if svc.HasIntegration(config.KindPostgres) || svc.HasIntegration(config.KindMySQL) {
+		migrator := ""
+		for _, name := range names {
+			if strings.Contains(name, "migrat") {
+				migrator = name
+				break
+			}
+		}
+		if migrator == "" {
+			report.add(SeverityError, art.Filename, 0, "no migration init container (a service named *migrate*)")
+		} else if appDeps[migrator] != "service_completed_successfully" {
+			report.add(SeverityWarning, art.Filename, 0, "service %s should wait for %s with condition: service_completed_successfully", app, migrator)
+		}
+	}
+	if cf.Services[app].Healthcheck == nil {
+		report.add(SeverityWarning, art.Filename, 0, "service %s has no healthcheck", app)
+	}
+
+	env, ok := findArtifact(artifacts, ".env.example")
+	if !ok {
+		report.add(SeverityError, ".env.example", 0, "no .env.example was generated")
+		return report
+	}
+	documented := map[string]bool{}
+	for _, line := range strings.Split(env.Content, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "export "))
+		if k, _, ok := strings.Cut(line, "="); ok && !strings.HasPrefix(line, "#") {
+			documented[strings.TrimSpace(k)] = true
+		}
+	}
+	reported := map[string]bool{}
+	for _, m := range composeVarRe.FindAllStringSubmatch(art.Content, -1) {
+		name, hasDefault := m[1], strings.HasPrefix(strings.TrimPrefix(m[2], ":"), "-")
+		if !documented[name] && !hasDefault && !reported[name] {
+			reported[name] = true
+			report.add(SeverityWarning, env.Filename, 0, "compose variable %s is not documented in .env.example", name)
+		}
+	}
+	return report
+}
+
+func imageMatches(image string, keywords []string) bool {
+	image = strings.ToLower(image)
+	for _, k := range keywords {
+		if strings.Contains(image, k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package verify
+
+import (
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/postprocess"
+)
+
+// CheckDependencies requires every third-party import to come from the
+// approved dependency list, every import of the service's own module to
+// name a generated package, and go.mod to declare the definition's module
+// path and require exactly the approved modules the code imports, at their
+// pinned versions
+func CheckDependencies(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Dependencies"}
+	module := svc.Module()
+
+	dirs := map[string]bool{}
+	for _, a := range artifacts {
+		if strings.HasSuffix(a.Filename, ".go") {
+			dirs[path.Dir(a.Filename)] = true
+		}
+	}
+	if len(dirs) == 0 {
+		return report
+	}
+
+	imported := map[string]bool{}
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, a.Filename, a.Content, parser.ImportsOnly)
+		if err != nil {
+			continue // reported by the formatting check
+		}
+		for _, imp := range f.Imports {
+			p, _ := strconv.Unquote(imp.Path.Value)
+			line := fset.Position(imp.Pos()).Line
+			switch {
+			case !strings.Contains(strings.Split(p, "/")[0], "."):
+				// standard library
+			case p == module || strings.HasPrefix(p, module+"/"):
+				if dir := strings.TrimPrefix(strings.TrimPrefix(p, module), "/"); !dirs[dir] && dir != "" {
+					report.add(SeverityError, a.Filename, line, "imports %s, which is not a package of the generated tree", p)
+				}
+			default:
+				if d, ok := config.ApprovedDependency(p); ok {
+					imported[d.Path] = true
+					continue
+				}
+				if alt := config.DependencyAlternative(p); alt != "" {
+					report.add(SeverityError, a.Filename, line, "imports %s, which is not on the dependency allow-list; use %s", p, alt)
+				} else {
+					report.add(SeverityError, a.Filename, line, "imports %s, which is not on the dependency allow-list", p)
+				}
+			}
+		}
+	}
+
+	var gomod *agents.Artifact
+	for i := range artifacts {
+		if artifacts[i].Filename == postprocess.GoModPath {
+			gomod = &artifacts[i]
+		}
+	}
+	if gomod == nil {
+		report.add(SeverityError, postprocess.GoModPath, 0, "no go.mod was generated")
+		return report
+	}
+	declared, required := parseGoMod(gomod.Content)
+	if declared != module {
+		report.add(SeverityError, gomod.Filename, 0, "declares module %q, want %q from the service definition", declared, module)
+	}
+	for _, p := range sortedKeys(required) {
+		version := required[p]
+		d, ok := config.ApprovedDependency(p)
+		switch {
+		case !ok || d.Path != p:
+			report.add(SeverityError, gomod.Filename, 0, "requires %s, which is not on the dependency allow-list", p)
+		case version != d.Version:
+			report.add(SeverityWarning, gomod.Filename, 0, "requires %s %s, but the allow-list pins %s", p, version, d.Version)
+		case !imported[p]:
+			report.add(SeverityWarning, gomod.Filename, 0, "requires %s, which no generated file imports", p)
+		}
+	}
+	for _, p := range sortedKeys(imported) {
+		if _, ok := required[p]; !ok {
+			report.add(SeverityError, gomod.Filename, 0, "does not require %s, which the generated code imports", p)
+		}
+	}
+	return report
+}
+
+// parseGoMod returns the module path and the required module versions of a go.mod file
+func parseGoMod(src string) (string, map[string]string) {
+	module := ""
+	required := map[string]string{}
+	inRequire := false
+	for _, line := range strings.Split(src, "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire && len(fields) >= 2:
+			required[fields[0]] = fields[1]
+		case fields[0] == "module" && len(fields) == 2:
+			module = strings.Trim(fields[1], `"`)
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+		case fields[0] == "require" && len(fields) >= 3:
+			required[fields[1]] = fields[2]
+		}
+	}
+	return module, required
+}
@@ -0,0 +1,297 @@
+package verify
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+// dockerInstruction is one logical Dockerfile instruction with continuations joined
+type dockerInstruction struct {
+	cmd  string // upper-case instruction, e.g. RUN
+	args string
+	line int
+}
+
+// dockerStage is a build stage started by FROM
+type dockerStage struct {
+	image        string
+	alias        string
+	line         int
+	instructions []dockerInstruction
+}
+
+var (
+	secretNameRe  = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|private_?key)`)
+	aptInstallRe  = regexp.MustCompile(`apt-get\s+(-\S+\s+)*install`)
+	apkAddRe      = regexp.MustCompile(`apk\s+(-\S+\s+)*add`)
+	pinnedPkgRe   = regexp.MustCompile(`^[A-Za-z0-9.+_-]+[=~<>]`)
+	archiveURLRe  = regexp.MustCompile(`(?i)(^https?://|\.(tar|tgz|tar\.gz|tar\.bz2|tar\.xz|zip)$)`)
+	rootUserRe    = regexp.MustCompile(`^(root|0)(:|$)`)
+	cdCommandRe   = regexp.MustCompile(`(^|&&|;)\s*cd\s+`)
+	shellPrefixRe = regexp.MustCompile(`^\[`)
+	shellSplitRe  = regexp.MustCompile(`&&|;|\|\|`)
+)
+
+// LintDockerfile applies hadolint-style rules to the generated Dockerfile(s),
+// using hadolint rule codes where one exists and LOTUS codes for the
+// pipeline's own requirements (multi-stage, non-root, healthcheck).
+func LintDockerfile(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Dockerfile lint"}
+
+	found := false
+	for _, a := range artifacts {
+		base := path.Base(a.Filename)
+		if base != "Dockerfile" && !strings.HasPrefix(base, "Dockerfile.") && !strings.HasSuffix(base, ".Dockerfile") {
+			continue
+		}
+		found = true
+		lintDockerfile(report, a.Filename, a.Content)
+	}
+	if !found {
+		report.add(SeverityError, "Dockerfile", 0, "no Dockerfile was generated")
+	}
+	return report
+}
+
+func lintDockerfile(report *Report, file, src string) {
+	lint := func(sev Severity, rule string, line int, format string, args ...any) {
+		report.add(sev, file, line, "["+rule+"] "+format, args...)
+	}
+
+	stages := parseDockerfile(src)
+	if len(stages) == 0 {
+		lint(SeverityError, "DL3061", 1, "Dockerfile must begin with FROM")
+		return
+	}
+	if len(stages) < 2 {
+		lint(SeverityError, "LOTUS001", stages[0].line, "use a multi-stage build so the runtime image excludes the toolchain")
+	}
+
+	aliases := map[string]bool{}
+	for i, st := range stages {
+		final := i == len(stages)-1
+		image := st.image
+		switch {
+		case aliases[strings.ToLower(image)], image == "scratch", strings.HasPrefix(image, "$"):
+		case strings.Contains(image, "@sha256:"):
+		case strings.HasSuffix(image, ":latest"):
+			lint(SeverityError, "DL3007", st.line, "using latest is prone to errors; pin %s to a version", image)
+		case !strings.Contains(path.Base(image), ":"):
+			lint(SeverityError, "DL3006", st.line, "always tag the image version explicitly (%s)", image)
+		}
+		if st.alias != "" {
+			aliases[strings.ToLower(st.alias)] = true
+		}
+
+		lastUser := ""
+		lastUserLine := st.line
+		workdirSet := false
+		hasHealthcheck := false
+		prevRun := false
+		for _, ins := range st.instructions {
+			switch ins.cmd {
+			case "USER":
+				lastUser, lastUserLine = strings.TrimSpace(ins.args), ins.line
+			case "WORKDIR":
+				workdirSet = true
+				wd := strings.Trim(strings.TrimSpace(ins.args), `"`)
+				if !strings.HasPrefix(wd, "/") && !strings.HasPrefix(wd, "$") {
+					lint(SeverityError, "DL3000", ins.line, "use absolute WORKDIR (%s)", wd)
+				}
+			case "HEALTHCHECK":
+				hasHealthcheck = true
+			case "CMD", "ENTRYPOINT":
+				if !shellPrefixRe.MatchString(strings.TrimSpace(ins.args)) {
+					lint(SeverityWarning, "DL3025", ins.line, "use JSON notation for %s", ins.cmd)
+				}
+			case "ADD":
+				for _, src := range dockerSources(ins.args) {
+					if !archiveURLRe.MatchString(src) {
+						lint(SeverityWarning, "DL3020", ins.line, "use COPY instead of ADD for files and folders (%s)", src)
+						break
+					}
+				}
+			case "COPY":
+				if dst := dockerDest(ins.args); dst != "" && !workdirSet && !strings.HasPrefix(dst, "/") && !strings.HasPrefix(dst, "$") {
+					lint(SeverityWarning, "DL3045", ins.line, "COPY to relative destination %s without WORKDIR set", dst)
+				}
+			case "ENV", "ARG":
+				for _, kv := range dockerKeyValues(ins.args) {
+					if secretNameRe.MatchString(kv[0]) && kv[1] != "" && !strings.HasPrefix(kv[1], "$") {
+						lint(SeverityError, "LOTUS002", ins.line, "%s %s bakes a secret into the image; inject it at runtime", ins.cmd, kv[0])
+					}
+				}
+			case "RUN":
+				lintDockerRun(lint, ins)
+				if prevRun {
+					lint(SeverityInfo, "DL3059", ins.line, "multiple consecutive RUN instructions; consider consolidating")
+				}
+			}
+			prevRun = ins.cmd == "RUN"
+		}
+
+		if final {
+			if lastUser == "" {
+				lint(SeverityError, "LOTUS003", st.line, "final stage runs as root; add a non-root USER")
+			} else if rootUserRe.MatchString(lastUser) {
+				lint(SeverityError, "DL3002", lastUserLine, "last USER should not be root")
+			}
+			if !hasHealthcheck && !strings.Contains(image, "distroless") && image != "scratch" {
+				lint(SeverityWarning, "LOTUS004", st.line, "final stage declares no HEALTHCHECK")
+			}
+		}
+	}
+}
+
+func lintDockerRun(lint func(Severity, string, int, string, ...any), ins dockerInstruction) {
+	run := ins.args
+	if strings.Contains(run, "sudo ") {
+		lint(SeverityError, "DL3004", ins.line, "do not use sudo; it leads to unpredictable behaviour")
+	}
+	if cdCommandRe.MatchString(run) {
+		lint(SeverityWarning, "DL3003", ins.line, "use WORKDIR to switch to a directory")
+	}
+	if aptInstallRe.MatchString(run) {
+		if !strings.Contains(run, "--no-install-recommends") {
+			lint(SeverityWarning, "DL3015", ins.line, "avoid additional packages by specifying --no-install-recommends")
+		}
+		if !strings.Contains(run, "rm -rf /var/lib/apt/lists") {
+			lint(SeverityWarning, "DL3009", ins.line, "delete the apt-get lists after installing something")
+		}
+		if unpinned := unpinnedPackages(run, aptInstallRe); len(unpinned) > 0 {
+			lint(SeverityWarning, "DL3008", ins.line, "pin versions in apt-get install (%s)", strings.Join(unpinned, ", "))
+		}
+	}
+	if apkAddRe.MatchString(run) {
+		if !strings.Contains(run, "--no-cache") {
+			lint(SeverityWarning, "DL3019", ins.line, "use the --no-cache switch to avoid keeping the apk index")
+		}
+		if unpinned := unpinnedPackages(run, apkAddRe); len(unpinned) > 0 {
+			lint(SeverityWarning, "DL3018", ins.line, "pin versions in apk add (%s)", strings.Join(unpinned, ", "))
+		}
+	}
+}
+
+// unpinnedPackages returns the packages of an install command without a version pin
+func unpinnedPackages(run string, install *regexp.Regexp) []string {
+	var out []string
+	for _, cmd := range shellSplitRe.Split(run, -1) {
+		loc := install.FindStringIndex(cmd)
+		if loc == nil {
+			continue
+		}
+		for _, tok := range strings.Fields(cmd[loc[1]:]) {
+			if strings.HasPrefix(tok, "-") || strings.HasPrefix(tok, "$") || tok == `\` {
+				continue
+			}
+			if !pinnedPkgRe.MatchString(tok) {
+				out = append(out, tok)
+			}
+		}
+	}
+	return out
+}
+
+// parseDockerfile splits a Dockerfile into stages of logical instructions
+func parseDockerfile(src string) []*dockerStage {
+	var stages []*dockerStage
+	var cur *dockerStage
+	lines := strings.Split(src, "\n")
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for strings.HasSuffix(line, `\`) && i+1 < len(lines) {
+			i++
+			next := strings.TrimSpace(lines[i])
+			if strings.HasPrefix(next, "#") {
+				next = `\`
+			}
+			line = strings.TrimSuffix(line, `\`) + " " + next
+		}
+		line = strings.TrimSuffix(line, `\`)
+		cmd, args, _ := strings.Cut(line, " ")
+		ins := dockerInstruction{cmd: strings.ToUpper(cmd), args: strings.TrimSpace(args), line: start}
+
+		if ins.cmd == "FROM" {
+			fields := strings.Fields(ins.args)
+			var image, alias string
+			for j := 0; j < len(fields); j++ {
+				f := fields[j]
+				switch {
+				case strings.HasPrefix(f, "--"):
+				case strings.EqualFold(f, "AS") && j+1 < len(fields):
+					alias = fields[j+1]
+					j++
+				case image == "":
+					image = f
+				}
+			}
+			cur = &dockerStage{image: image, alias: alias, line: start}
+			stages = append(stages, cur)
+			continue
+		}
+		if cur != nil {
+			cur.instructions = append(cur.instructions, ins)
+		}
+	}
+	return stages
+}
+
+// dockerSources returns the source operands of a COPY/ADD instruction
+func dockerSources(args string) []string {
+	parts := dockerOperands(args)
+	if len(parts) < 2 {
+		return nil
+	}
+	return parts[:len(parts)-1]
+}
+
+// dockerDest returns the destination operand of a COPY/ADD instruction
+func dockerDest(args string) string {
+	parts := dockerOperands(args)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
+func dockerOperands(args string) []string {
+	args = strings.TrimSpace(args)
+	if strings.HasPrefix(args, "[") {
+		var parts []string
+		if json.Unmarshal([]byte(args), &parts) == nil {
+			return parts
+		}
+	}
+	var parts []string
+	for _, f := range strings.Fields(args) {
+		if !strings.HasPrefix(f, "--") {
+			parts = append(parts, f)
+		}
+	}
+	return parts
+}
+
+// dockerKeyValues parses "K=V K2=V2" and legacy "K V" forms
+func dockerKeyValues(args string) [][2]string {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return nil
+	}
+	if !strings.Contains(fields[0], "=") {
+		return [][2]string{{fields[0], strings.TrimSpace(strings.TrimPrefix(args, fields[0]))}}
+	}
+	var out [][2]string
+	for _, f := range fields {
+		k, v, _ := strings.Cut(f, "=")
+		out = append(out, [2]string{k, strings.Trim(v, `"'`)})
+	}
+	return out
+}
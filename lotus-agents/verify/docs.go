@@ -0,0 +1,203 @@
+package verify
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+var (
+	inlineCodeRe  = regexp.MustCompile("`([^`\n]+)`")
+	adrHeadingRe  = regexp.MustCompile(`(?im)^#+\s*(status|context|decision|consequences)\b`)
+	docEndpointRe = regexp.MustCompile(`\b(GET|POST|PUT|PATCH|DELETE)\s+(/[^\s` + "`" + `"')]*)`)
+	adrSections   = []string{"status", "context", "decision", "consequences"}
+)
+
+// runbookIncidents are the incidents every runbook must cover, with the
+// keywords that show it does; kafka marks incidents only brokers can have
+var runbookIncidents = []struct {
+	name     string
+	keywords []string
+	kafka    bool
+}{
+	{"migration failures", []string{"migration"}, false},
+	{"DLQ growth", []string{"dlq", "dead letter", "dead-letter"}, true},
+}
+
+// CheckDocumentation verifies the Documentation agent's output: a README,
+// C4 diagrams, complete ADRs, a runbook covering the service's incidents, and
+// commands and endpoints that exist in the generated Makefile and API contract
+func CheckDocumentation(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Documentation"}
+
+	var docs []agents.Artifact
+	for _, a := range artifacts {
+		if a.Filename == agents.DocsReadmePath || strings.HasPrefix(a.Filename, "docs/") && strings.HasSuffix(a.Filename, ".md") {
+			docs = append(docs, a)
+		}
+	}
+	byName := map[string]agents.Artifact{}
+	for _, d := range docs {
+		byName[d.Filename] = d
+	}
+	for _, p := range []string{agents.DocsReadmePath, agents.DocsArchitecturePath, agents.DocsRunbookPath, agents.DocsAPIGuidePath} {
+		if _, ok := byName[p]; !ok {
+			report.add(SeverityError, p, 0, "not generated")
+		}
+	}
+
+	if arch, ok := byName[agents.DocsArchitecturePath]; ok {
+		for _, diagram := range []string{"C4Context", "C4Container"} {
+			if !strings.Contains(arch.Content, diagram) {
+				report.add(SeverityWarning, arch.Filename, 0, "no %s Mermaid diagram", diagram)
+			}
+		}
+	}
+
+	var adrs []agents.Artifact
+	for _, d := range docs {
+		if strings.HasPrefix(d.Filename, agents.DocsADRDir) {
+			adrs = append(adrs, d)
+		}
+	}
+	if len(adrs) == 0 {
+		report.add(SeverityError, agents.DocsADRDir, 0, "no ADRs were generated")
+	}
+	outbox := false
+	for _, adr := range adrs {
+		found := map[string]bool{}
+		for _, m := range adrHeadingRe.FindAllStringSubmatch(adr.Content, -1) {
+			found[strings.ToLower(m[1])] = true
+		}
+		for _, s := range adrSections {
+			if !found[s] {
+				report.add(SeverityWarning, adr.Filename, 0, "ADR has no %s section", s)
+			}
+		}
+		outbox = outbox || strings.Contains(strings.ToLower(adr.Content), "outbox")
+	}
+	if len(adrs) > 0 && svc.HasIntegration(config.KindKafka) && !outbox {
+		report.add(SeverityWarning, agents.DocsADRDir, 0, "no ADR records the transactional outbox decision")
+	}
+
+	if runbook, ok := byName[agents.DocsRunbookPath]; ok {
+		lower := strings.ToLower(runbook.Content)
+		for _, inc := range runbookIncidents {
+			if inc.kafka && !svc.HasIntegration(config.KindKafka) {
+				continue
+			}
+			covered := false
+			for _, kw := range inc.keywords {
+				covered = covered || strings.Contains(lower, kw)
+			}
+			if !covered {
+				report.add(SeverityWarning, runbook.Filename, 0, "runbook does not cover %s", inc.name)
+			}
+		}
+	}
+
+	checkDocMakeTargets(report, docs, artifacts)
+	checkDocEndpoints(report, docs, artifacts)
+	return report
+}
+
+// checkDocMakeTargets reports make invocations in code spans and blocks that
+// name targets the generated Makefile does not declare
+func checkDocMakeTargets(report *Report, docs, artifacts []agents.Artifact) {
+	var mk *spec.Makefile
+	for _, a := range artifacts {
+		if path.Base(a.Filename) == "Makefile" {
+			mk = spec.ParseMakefile(a.Content)
+		}
+	}
+	if mk == nil {
+		return
+	}
+	for _, d := range docs {
+		reported := map[string]bool{}
+		for _, code := range docCodeLines(d.Content) {
+			for _, t := range makeTargets(code.text) {
+				if !mk.Has(t) && !reported[t] {
+					reported[t] = true
+					report.add(SeverityError, d.Filename, code.line, "documents make %s, which the Makefile does not declare", t)
+				}
+			}
+		}
+	}
+}
+
+// checkDocEndpoints reports "METHOD /path" references that match no endpoint in the OpenAPI contract
+func checkDocEndpoints(report *Report, docs, artifacts []agents.Artifact) {
+	art, ok := findArtifact(artifacts, OpenAPIPath)
+	if !ok {
+		return
+	}
+	doc, err := spec.ParseOpenAPI([]byte(art.Content))
+	if err != nil {
+		return // reported by the OpenAPI check
+	}
+	bases := append([]string{""}, doc.BasePaths()...)
+	eps := doc.Endpoints()
+	for _, d := range docs {
+		reported := map[string]bool{}
+		for i, line := range strings.Split(d.Content, "\n") {
+			for _, m := range docEndpointRe.FindAllStringSubmatch(line, -1) {
+				p := strings.TrimRight(m[2], ".,:;")
+				if j := strings.IndexAny(p, "?#"); j >= 0 {
+					p = p[:j]
+				}
+				if isInfraPath(p) {
+					continue
+				}
+				found := false
+				for _, ep := range eps {
+					if ep.Method == m[1] && routeMatches(NormalizePath(p), NormalizePath(ep.Path), bases) {
+						found = true
+						break
+					}
+				}
+				key := fmt.Sprintf("%s %s", m[1], p)
+				if !found && !reported[key] {
+					reported[key] = true
+					report.add(SeverityWarning, d.Filename, i+1, "documents %s, which is not in %s", key, OpenAPIPath)
+				}
+			}
+		}
+	}
+}
+
+// docCode is a line of code in a Markdown document
+type docCode struct {
+	line int
+	text string
+}
+
+// docCodeLines returns the code in a Markdown document: every line of a
+// fenced block and the inline code spans of every other line
+func docCodeLines(content string) []docCode {
+	var code []docCode
+	inBlock := false
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inBlock = !inBlock
+			continue
+		}
+		if inBlock {
+			code = append(code, docCode{i + 1, line})
+			continue
+		}
+		var spans []string
+		for _, m := range inlineCodeRe.FindAllStringSubmatch(line, -1) {
+			spans = append(spans, m[1])
+		}
+		if len(spans) > 0 {
+			code = append(code, docCode{i + 1, strings.Join(spans, "; ")})
+		}
+	}
+	return code
+}
@@ -0,0 +1,256 @@
+package verify
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// dtoDir is the Clean Architecture layer holding HTTP request/response types
+const dtoDir = "internal/interfaces/http/dto/"
+
+// dtoField is a JSON-visible field of a generated DTO struct
+type dtoField struct {
+	JSONName  string
+	GoType    string
+	OmitEmpty bool
+	Line      int
+}
+
+// dtoStruct is a struct type declared in the DTO layer
+type dtoStruct struct {
+	Name     string
+	File     string
+	Line     int
+	Fields   []dtoField
+	Embedded []string
+}
+
+// CheckDTOConformance verifies that the DTO structs generated under
+// internal/interfaces/http/dto match the component schemas of the OpenAPI
+// contract: same property names as json tags, compatible types and
+// omitempty only on optional properties.
+func CheckDTOConformance(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "DTO contract conformance"}
+
+	art, ok := findArtifact(artifacts, OpenAPIPath)
+	if !ok {
+		report.add(SeverityError, OpenAPIPath, 0, "no OpenAPI document available")
+		return report
+	}
+	doc, err := spec.ParseOpenAPI([]byte(art.Content))
+	if err != nil {
+		report.add(SeverityError, art.Filename, 0, "%v", err)
+		return report
+	}
+
+	structs := map[string]*dtoStruct{}
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, dtoDir) || !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		if err := parseDTOs(a.Filename, a.Content, structs); err != nil {
+			report.add(SeverityError, a.Filename, 0, "cannot parse DTO file: %v", err)
+		}
+	}
+	if len(structs) == 0 {
+		report.add(SeverityError, dtoDir, 0, "no DTO structs were generated")
+		return report
+	}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		props, required := schemaProperties(doc, doc.Components.Schemas[name])
+		if len(props) == 0 {
+			continue
+		}
+		st := matchDTO(structs, name)
+		if st == nil {
+			report.add(SeverityWarning, art.Filename, 0, "schema %s has no matching DTO struct", name)
+			continue
+		}
+		fields := flattenFields(structs, st, 0)
+
+		byName := map[string]dtoField{}
+		for _, f := range fields {
+			byName[f.JSONName] = f
+		}
+		propNames := make([]string, 0, len(props))
+		for p := range props {
+			propNames = append(propNames, p)
+		}
+		sort.Strings(propNames)
+
+		for _, p := range propNames {
+			f, ok := byName[p]
+			if !ok {
+				report.add(SeverityError, st.File, st.Line, "%s is missing property %q from schema %s", st.Name, p, name)
+				continue
+			}
+			if required[p] && f.OmitEmpty {
+				report.add(SeverityWarning, st.File, f.Line, "%s.%s is required by schema %s but tagged omitempty", st.Name, p, name)
+			}
+			if s, ok := doc.ResolveSchema(props[p]); ok && !typeCompatible(s.Type.Primary(), f.GoType) {
+				report.add(SeverityWarning, st.File, f.Line, "%s.%s has Go type %s but schema %s declares %s", st.Name, p, f.GoType, name, s.Type.Primary())
+			}
+		}
+		for _, f := range fields {
+			if _, ok := props[f.JSONName]; !ok {
+				report.add(SeverityError, st.File, f.Line, "%s.%s is not a property of schema %s", st.Name, f.JSONName, name)
+			}
+		}
+	}
+	return report
+}
+
+func parseDTOs(filename, src string, out map[string]*dtoStruct) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, sp := range gen.Specs {
+			ts := sp.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			d := &dtoStruct{Name: ts.Name.Name, File: filename, Line: fset.Position(ts.Pos()).Line}
+			for _, field := range st.Fields.List {
+				tag := ""
+				if field.Tag != nil {
+					tag = reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+				}
+				if tag == "-" {
+					continue
+				}
+				jsonName, opts, _ := strings.Cut(tag, ",")
+				omit := strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero")
+				if len(field.Names) == 0 {
+					if jsonName == "" {
+						d.Embedded = append(d.Embedded, strings.TrimPrefix(types.ExprString(field.Type), "*"))
+						continue
+					}
+					field.Names = []*ast.Ident{ast.NewIdent(types.ExprString(field.Type))}
+				}
+				for _, n := range field.Names {
+					if !n.IsExported() {
+						continue
+					}
+					name := jsonName
+					if name == "" {
+						name = n.Name
+					}
+					d.Fields = append(d.Fields, dtoField{
+						JSONName:  name,
+						GoType:    types.ExprString(field.Type),
+						OmitEmpty: omit,
+						Line:      fset.Position(n.Pos()).Line,
+					})
+				}
+			}
+			out[d.Name] = d
+		}
+	}
+	return nil
+}
+
+// matchDTO finds the struct implementing a schema, tolerating common suffixes
+func matchDTO(structs map[string]*dtoStruct, schema string) *dtoStruct {
+	want := strings.ToLower(schema)
+	for _, suffix := range []string{"", "dto", "request", "response", "requestdto", "responsedto"} {
+		for name, st := range structs {
+			if strings.ToLower(name) == want+suffix {
+				return st
+			}
+		}
+	}
+	return nil
+}
+
+// flattenFields returns the struct's fields including promoted fields of embedded DTOs
+func flattenFields(structs map[string]*dtoStruct, st *dtoStruct, depth int) []dtoField {
+	fields := append([]dtoField(nil), st.Fields...)
+	if depth > 8 {
+		return fields
+	}
+	for _, e := range st.Embedded {
+		if inner, ok := structs[e[strings.LastIndex(e, ".")+1:]]; ok {
+			fields = append(fields, flattenFields(structs, inner, depth+1)...)
+		}
+	}
+	return fields
+}
+
+// schemaProperties merges properties and required lists across allOf members
+func schemaProperties(doc *spec.OpenAPI, s *spec.Schema) (map[string]*spec.Schema, map[string]bool) {
+	props := map[string]*spec.Schema{}
+	required := map[string]bool{}
+	var walk func(s *spec.Schema, depth int)
+	walk = func(s *spec.Schema, depth int) {
+		s, ok := doc.ResolveSchema(s)
+		if !ok || depth > 8 {
+			return
+		}
+		for name, p := range s.Properties {
+			props[name] = p
+		}
+		for _, r := range s.Required {
+			required[r] = true
+		}
+		for _, sub := range s.AllOf {
+			walk(sub, depth+1)
+		}
+	}
+	walk(s, 0)
+	return props, required
+}
+
+// typeCompatible reports whether a Go type can carry a JSON Schema type.
+// Named types it does not recognise are assumed compatible.
+func typeCompatible(schemaType, goType string) bool {
+	base := strings.TrimPrefix(goType, "*")
+	switch schemaType {
+	case "", "object":
+		return true
+	case "array":
+		return strings.HasPrefix(base, "[]") || !isBuiltinScalar(base)
+	case "string":
+		return base == "string" || base == "[]byte" || !isBuiltinScalar(base) && !strings.HasPrefix(base, "[]") && !strings.HasPrefix(base, "map[")
+	case "integer":
+		return strings.HasPrefix(base, "int") || strings.HasPrefix(base, "uint") || !isBuiltinScalar(base) && !strings.HasPrefix(base, "[]")
+	case "number":
+		return strings.HasPrefix(base, "float") || strings.HasPrefix(base, "int") || strings.HasPrefix(base, "uint") || !isBuiltinScalar(base) && !strings.HasPrefix(base, "[]")
+	case "boolean":
+		return base == "bool" || !isBuiltinScalar(base) && !strings.HasPrefix(base, "[]")
+	}
+	return true
+}
+
+func isBuiltinScalar(t string) bool {
+	switch t {
+	case "string", "bool", "byte", "rune",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return true
+	}
+	return false
+}
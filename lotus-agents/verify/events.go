@@ -0,0 +1,84 @@
+package verify
+
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/postprocess"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// requiredEventFields are the metadata fields the Messaging agent's prompt
+// promises on every domain event
+var requiredEventFields = []string{"EventID", "CorrelationID", "Timestamp", "Version"}
+
+// forbiddenEventImports must never appear in internal/domain/event (Dependency Rule)
+var forbiddenEventImports = []string{"net/http", "database/sql", "kafka", "sarama", "franz-go", "pgx"}
+
+// CheckEventStructs verifies that every domain event struct carries the
+// EventID, CorrelationID, Timestamp and Version fields (directly or through an
+// embedded metadata struct), that the event package imports no infrastructure
+// SDKs, and that each event declares its topic for the AsyncAPI catalog.
+func CheckEventStructs(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Domain event schema"}
+
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, postprocess.EventDir) || !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, a.Filename, a.Content, parser.ImportsOnly)
+		if err != nil {
+			continue // reported below by ParseEventArtifacts
+		}
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			for _, bad := range forbiddenEventImports {
+				if strings.Contains(path, bad) {
+					report.add(SeverityError, a.Filename, fset.Position(imp.Pos()).Line, "domain event package imports %q; domain/event must have zero infrastructure imports", path)
+				}
+			}
+		}
+	}
+
+	all, err := postprocess.ParseEventArtifacts(artifacts)
+	if err != nil {
+		report.add(SeverityError, "", 0, "cannot parse event structs: %v", err)
+		return report
+	}
+	events, _ := spec.ClassifyEvents(all)
+	if len(events) == 0 {
+		report.add(SeverityError, postprocess.EventDir, 0, "no domain event structs were generated")
+		return report
+	}
+
+	byName := map[string]*spec.Event{}
+	for _, e := range all {
+		byName[e.Name] = e
+	}
+	for _, e := range events {
+		have := map[string]bool{}
+		for _, f := range e.AllFields(byName) {
+			have[f.Name] = true
+		}
+		var missing []string
+		for _, want := range requiredEventFields {
+			if !have[want] {
+				missing = append(missing, want)
+			}
+		}
+		if len(missing) > 0 {
+			report.add(SeverityError, e.File, e.Line, "event %s is missing required field(s) %s", e.Name, strings.Join(missing, ", "))
+		}
+		if e.Topic == "" {
+			report.add(SeverityWarning, e.File, e.Line, "event %s has no //asyncapi:topic directive; catalog uses derived topic", e.Name)
+		}
+		if e.Direction == "" {
+			report.add(SeverityInfo, e.File, e.Line, "event %s has no //asyncapi:direction directive; direction inferred from consumers", e.Name)
+		}
+	}
+	return report
+}
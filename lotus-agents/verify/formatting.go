@@ -0,0 +1,22 @@
+package verify
+
+import (
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/postprocess"
+)
+
+// CheckFormatting reports the Go, YAML and JSON files the formatter left as
+// generated because they do not parse, and Go files using a package the
+// import fixer could not resolve
+func CheckFormatting(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Formatting"}
+	_, problems := postprocess.Format(artifacts)
+	for _, p := range problems {
+		if p.Parse {
+			report.add(SeverityError, p.File, p.Line, "does not parse, left unformatted: %s", p.Message)
+			continue
+		}
+		report.add(SeverityWarning, p.File, p.Line, "%s", p.Message)
+	}
+	return report
+}
@@ -0,0 +1,341 @@
+package verify
+
+import (
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+// graphqlBuiltinScalars are always defined in a GraphQL schema
+var graphqlBuiltinScalars = map[string]bool{"ID": true, "String": true, "Int": true, "Float": true, "Boolean": true}
+
+type gqlToken struct {
+	text string
+	line int
+}
+
+type gqlField struct {
+	name       string
+	line       int
+	directives []string
+}
+
+type gqlType struct {
+	kind       string
+	name       string
+	file       string
+	line       int
+	directives []string
+	fields     []gqlField
+}
+
+type gqlRef struct {
+	name string
+	file string
+	line int
+}
+
+type gqlSchema struct {
+	types      map[string]*gqlType
+	directives map[string]bool
+	refs       []gqlRef
+}
+
+// CheckGraphQLSchema parses the generated SDL files and checks that every
+// referenced type is defined, that an @auth directive is declared and
+// applied to every mutation, and that dataloaders and gqlgen config exist.
+func CheckGraphQLSchema(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "GraphQL schema"}
+
+	schema := &gqlSchema{types: map[string]*gqlType{}, directives: map[string]bool{}}
+	found := false
+	hasLoaders := false
+	for _, a := range artifacts {
+		switch {
+		case strings.HasSuffix(a.Filename, ".graphqls") || strings.HasSuffix(a.Filename, ".graphql"):
+			found = true
+			parseGraphQL(a.Filename, a.Content, schema)
+		case strings.Contains(a.Filename, "internal/interfaces/graphql/dataloader/"):
+			hasLoaders = true
+		}
+	}
+	if !found {
+		report.add(SeverityError, "", 0, "no GraphQL schema (.graphqls) was generated")
+		return report
+	}
+	if _, ok := findArtifact(artifacts, "gqlgen.yml"); !ok {
+		report.add(SeverityWarning, "gqlgen.yml", 0, "no gqlgen configuration was generated")
+	}
+	if !hasLoaders {
+		report.add(SeverityWarning, "internal/interfaces/graphql/dataloader", 0, "no dataloaders were generated; relation fields are exposed to N+1 queries")
+	}
+
+	if _, ok := schema.types["Query"]; !ok {
+		report.add(SeverityError, "", 0, "schema does not define a Query type")
+	}
+
+	seen := map[string]bool{}
+	for _, ref := range schema.refs {
+		if graphqlBuiltinScalars[ref.name] || schema.types[ref.name] != nil || seen[ref.name] {
+			continue
+		}
+		seen[ref.name] = true
+		report.add(SeverityError, ref.file, ref.line, "type %s is referenced but never defined", ref.name)
+	}
+
+	if !schema.directives["auth"] {
+		report.add(SeverityError, "", 0, "schema does not declare an @auth directive")
+	}
+	if m, ok := schema.types["Mutation"]; ok && !contains(m.directives, "auth") {
+		for _, f := range m.fields {
+			if !contains(f.directives, "auth") {
+				report.add(SeverityError, m.file, f.line, "mutation %s is not protected by @auth", f.name)
+			}
+		}
+	}
+	return report
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func tokenizeGraphQL(src string) []gqlToken {
+	var toks []gqlToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			if end < 0 {
+				end = len(src) - i - 3
+			}
+			line += strings.Count(src[i:i+3+end], "\n")
+			i += end + 6
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			i = j + 1
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-':
+			j := i
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || src[j] >= 'a' && src[j] <= 'z' || src[j] >= 'A' && src[j] <= 'Z' || src[j] >= '0' && src[j] <= '9' || src[j] == '-') {
+				j++
+			}
+			toks = append(toks, gqlToken{text: src[i:j], line: line})
+			i = j
+		default:
+			toks = append(toks, gqlToken{text: string(c), line: line})
+			i++
+		}
+	}
+	return toks
+}
+
+// gqlParser is a tolerant parser for type system definitions. String
+// descriptions are dropped by the tokenizer.
+type gqlParser struct {
+	toks   []gqlToken
+	pos    int
+	file   string
+	schema *gqlSchema
+}
+
+func (p *gqlParser) peek() gqlToken {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return gqlToken{}
+}
+
+func (p *gqlParser) next() gqlToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *gqlParser) done() bool { return p.pos >= len(p.toks) }
+
+// skipBalanced consumes a bracketed group starting at the current token
+func (p *gqlParser) skipBalanced(open, close string) {
+	if p.peek().text != open {
+		return
+	}
+	depth := 0
+	for !p.done() {
+		switch p.next().text {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+// skipDefault consumes an optional "= value" default
+func (p *gqlParser) skipDefault() {
+	if p.peek().text != "=" {
+		return
+	}
+	p.next()
+	switch p.peek().text {
+	case "[":
+		p.skipBalanced("[", "]")
+	case "{":
+		p.skipBalanced("{", "}")
+	default:
+		p.next()
+	}
+}
+
+func (p *gqlParser) parseDirectives() []string {
+	var names []string
+	for p.peek().text == "@" {
+		p.next()
+		names = append(names, p.next().text)
+		p.skipBalanced("(", ")")
+	}
+	return names
+}
+
+// parseTypeRef consumes a type reference such as [Order!]! and records it
+func (p *gqlParser) parseTypeRef() {
+	for p.peek().text == "[" {
+		p.next()
+	}
+	t := p.next()
+	if t.text != "" && t.text != "]" {
+		p.schema.refs = append(p.schema.refs, gqlRef{name: t.text, file: p.file, line: t.line})
+	}
+	for p.peek().text == "!" || p.peek().text == "]" {
+		p.next()
+	}
+}
+
+func parseGraphQL(filename, src string, schema *gqlSchema) {
+	p := &gqlParser{toks: tokenizeGraphQL(src), file: filename, schema: schema}
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "extend":
+		case "schema":
+			p.parseDirectives()
+			p.skipBalanced("{", "}")
+		case "scalar":
+			p.define(&gqlType{kind: t.text, name: p.next().text, line: t.line, directives: p.parseDirectives()})
+		case "directive":
+			p.next() // @
+			schema.directives[p.next().text] = true
+			p.skipBalanced("(", ")")
+			if p.peek().text == "repeatable" {
+				p.next()
+			}
+			if p.peek().text == "on" {
+				p.next()
+				if p.peek().text == "|" {
+					p.next()
+				}
+				p.next()
+				for p.peek().text == "|" {
+					p.next()
+					p.next()
+				}
+			}
+		case "enum":
+			p.define(&gqlType{kind: t.text, name: p.next().text, line: t.line, directives: p.parseDirectives()})
+			p.skipBalanced("{", "}")
+		case "union":
+			p.define(&gqlType{kind: t.text, name: p.next().text, line: t.line, directives: p.parseDirectives()})
+			if p.peek().text == "=" {
+				p.next()
+				if p.peek().text == "|" {
+					p.next()
+				}
+				p.parseTypeRef()
+				for p.peek().text == "|" {
+					p.next()
+					p.parseTypeRef()
+				}
+			}
+		case "type", "interface", "input":
+			typ := &gqlType{kind: t.text, name: p.next().text, line: t.line}
+			if p.peek().text == "implements" {
+				p.next()
+				for p.peek().text != "{" && p.peek().text != "@" && !p.done() {
+					if tok := p.next(); tok.text != "&" {
+						schema.refs = append(schema.refs, gqlRef{name: tok.text, file: filename, line: tok.line})
+					}
+				}
+			}
+			typ.directives = p.parseDirectives()
+			if p.peek().text == "{" {
+				p.next()
+				p.parseFields(typ)
+			}
+			p.define(typ)
+		}
+	}
+}
+
+// define registers a type, merging fields of extended types
+func (p *gqlParser) define(t *gqlType) {
+	t.file = p.file
+	if prev, ok := p.schema.types[t.name]; ok {
+		prev.fields = append(prev.fields, t.fields...)
+		prev.directives = append(prev.directives, t.directives...)
+		return
+	}
+	p.schema.types[t.name] = t
+}
+
+func (p *gqlParser) parseFields(typ *gqlType) {
+	for !p.done() {
+		t := p.next()
+		if t.text == "}" {
+			return
+		}
+		f := gqlField{name: t.text, line: t.line}
+		if p.peek().text == "(" {
+			p.next()
+			for !p.done() && p.peek().text != ")" {
+				p.next() // argument name
+				if p.peek().text == ":" {
+					p.next()
+					p.parseTypeRef()
+				}
+				p.skipDefault()
+				p.parseDirectives()
+			}
+			p.next() // )
+		}
+		if p.peek().text == ":" {
+			p.next()
+			p.parseTypeRef()
+		}
+		p.skipDefault()
+		f.directives = p.parseDirectives()
+		typ.fields = append(typ.fields, f)
+	}
+}
@@ -0,0 +1,141 @@
+package verify
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+)
+
+// Packages the integration adapters are expected to use
+const (
+	circuitBreakerImport = "github.com/sony/gobreaker"
+	httptestImport       = "net/http/httptest"
+	applicationDir       = "internal/application/"
+)
+
+// CheckIntegrations verifies the outbound adapters: every external API has an
+// adapter asserting an output port from application/port, with a client
+// timeout, retries, a circuit breaker and a fake server, and no application
+// code calls an external API without going through a port
+func CheckIntegrations(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "External integrations"}
+
+	ports := map[string]bool{}
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, portDir) || !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), a.Filename, a.Content, 0)
+		if err != nil {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if _, ok := ts.Type.(*ast.InterfaceType); ok {
+					ports[ts.Name.Name] = true
+				}
+				return false
+			}
+			return true
+		})
+	}
+
+	for _, in := range svc.ExternalAPIs() {
+		dir := agents.IntegrationDir + in.Package() + "/"
+		var code, fakes []agents.Artifact
+		for _, a := range artifacts {
+			if !strings.Contains(a.Filename, dir) || !strings.HasSuffix(a.Filename, ".go") || strings.HasSuffix(a.Filename, "_test.go") {
+				continue
+			}
+			if strings.Contains(a.Filename, dir+"fake/") {
+				fakes = append(fakes, a)
+			} else {
+				code = append(code, a)
+			}
+		}
+		if len(code) == 0 {
+			report.add(SeverityError, dir, 0, "no client adapter for %s", in.Name)
+			continue
+		}
+
+		implements, timeout, retries, breaker := false, false, false, false
+		for _, a := range code {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, a.Filename, a.Content, 0)
+			if err != nil {
+				report.add(SeverityError, a.Filename, 0, "cannot parse: %v", err)
+				continue
+			}
+			for _, imp := range file.Imports {
+				path, _ := strconv.Unquote(imp.Path.Value)
+				breaker = breaker || strings.HasPrefix(path, circuitBreakerImport)
+			}
+			lower := strings.ToLower(a.Content)
+			timeout = timeout || strings.Contains(a.Content, "Timeout")
+			retries = retries || strings.Contains(lower, "retry") || strings.Contains(lower, "backoff")
+			breaker = breaker || strings.Contains(lower, "breaker")
+
+			for _, decl := range file.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.VAR {
+					continue
+				}
+				for _, sp := range gd.Specs {
+					vs := sp.(*ast.ValueSpec)
+					if len(vs.Names) == 1 && vs.Names[0].Name == "_" && ports[typeName(vs.Type)] {
+						implements = true
+					}
+				}
+			}
+		}
+		if !implements {
+			report.add(SeverityError, dir, 0, "%s adapter does not assert an output port from %s (var _ port.X = (*Client)(nil))", in.Name, portDir)
+		}
+		if !timeout {
+			report.add(SeverityWarning, dir, 0, "%s adapter sets no client timeout", in.Name)
+		}
+		if !retries {
+			report.add(SeverityWarning, dir, 0, "%s adapter does not retry transient failures", in.Name)
+		}
+		if !breaker {
+			report.add(SeverityWarning, dir, 0, "%s adapter has no circuit breaker (%s)", in.Name, circuitBreakerImport)
+		}
+
+		hasFake := false
+		for _, a := range fakes {
+			hasFake = hasFake || strings.Contains(a.Content, strconv.Quote(httptestImport))
+		}
+		if !hasFake {
+			report.add(SeverityWarning, dir+"fake/", 0, "no httptest fake server for %s", in.Name)
+		}
+	}
+
+	checkInlineClients(report, artifacts)
+	return report
+}
+
+// checkInlineClients reports application code that imports net/http or an
+// integration adapter instead of depending on an output port
+func checkInlineClients(report *Report, artifacts []agents.Artifact) {
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, applicationDir) || !strings.HasSuffix(a.Filename, ".go") || strings.HasSuffix(a.Filename, "_test.go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, a.Filename, a.Content, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			if path == "net/http" || strings.Contains(path+"/", "/"+agents.IntegrationDir) {
+				report.add(SeverityError, a.Filename, fset.Position(imp.Pos()).Line, "application code imports %s; call external APIs through an output port", path)
+			}
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kustomize layout the Kubernetes agent writes
+const (
+	K8sDir         = "deploy/k8s"
+	K8sBaseDir     = K8sDir + "/base"
+	K8sOverlaysDir = K8sDir + "/overlays"
+)
+
+// requiredK8sKinds must each appear at least once in the base
+var requiredK8sKinds = []string{"Deployment", "Service", "ConfigMap", "HorizontalPodAutoscaler", "PodDisruptionBudget", "NetworkPolicy"}
+
+// k8sObject is one YAML document of a manifest file
+type k8sObject struct {
+	file string
+	doc  map[string]any
+}
+
+func (o k8sObject) kind() string       { return str(o.doc, "kind") }
+func (o k8sObject) apiVersion() string { return str(o.doc, "apiVersion") }
+func (o k8sObject) name() string       { return str(o.doc, "metadata", "name") }
+
+// CheckKubernetesManifests validates the Kustomize base under deploy/k8s
+// against the bundled Kubernetes OpenAPI definitions and checks that probes
+// hit health routes registered in the generated code, containers declare
+// resource requests, secrets are referenced rather than inlined, selectors
+// line up, and the NetworkPolicy allows egress to every declared integration.
+func CheckKubernetesManifests(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Kubernetes manifests"}
+
+	bundle, err := kubernetesSchemas()
+	if err != nil {
+		report.add(SeverityError, "", 0, "%v", err)
+		return report
+	}
+
+	var base []k8sObject
+	kustomizations := map[string]map[string]any{}
+	baseFiles := map[string]bool{}
+	for _, a := range artifacts {
+		if !strings.HasPrefix(a.Filename, K8sDir+"/") || !isYAMLFile(a.Filename) {
+			continue
+		}
+		docs, err := decodeYAMLDocuments(a.Content)
+		if err != nil {
+			report.add(SeverityError, a.Filename, 0, "cannot parse manifest: %v", err)
+			continue
+		}
+		if path.Base(a.Filename) == "kustomization.yaml" || path.Base(a.Filename) == "kustomization.yml" {
+			if len(docs) > 0 {
+				kustomizations[path.Dir(a.Filename)] = docs[0]
+			}
+			continue
+		}
+		if !strings.HasPrefix(a.Filename, K8sBaseDir+"/") {
+			// overlay patches are partial objects and are not schema-validated
+			continue
+		}
+		baseFiles[strings.TrimPrefix(a.Filename, K8sBaseDir+"/")] = true
+		for _, doc := range docs {
+			obj := k8sObject{file: a.Filename, doc: doc}
+			base = append(base, obj)
+			if obj.apiVersion() == "" || obj.kind() == "" {
+				report.add(SeverityError, a.Filename, 0, "document is missing apiVersion or kind")
+				continue
+			}
+			schema, ok := bundle.lookup(obj.apiVersion(), obj.kind())
+			if !ok {
+				report.add(SeverityInfo, a.Filename, 0, "%s %s is not in the bundled schema; not validated", obj.apiVersion(), obj.kind())
+				continue
+			}
+			for _, msg := range bundle.validate(schema, doc, "") {
+				report.add(SeverityError, a.Filename, 0, "%s %s: %s", obj.kind(), obj.name(), msg)
+			}
+		}
+	}
+	if len(base) == 0 {
+		report.add(SeverityError, K8sBaseDir, 0, "no Kubernetes manifests were generated")
+		return report
+	}
+
+	checkKustomizations(report, kustomizations, baseFiles)
+
+	byKind := map[string][]k8sObject{}
+	for _, o := range base {
+		byKind[o.kind()] = append(byKind[o.kind()], o)
+	}
+	for _, kind := range requiredK8sKinds {
+		if len(byKind[kind]) == 0 {
+			report.add(SeverityError, K8sBaseDir, 0, "base declares no %s", kind)
+		}
+	}
+
+	healthPaths := healthRoutes(artifacts)
+	for _, d := range byKind["Deployment"] {
+		podLabels := stringMap(get(d.doc, "spec", "template", "metadata", "labels"))
+		if sel := stringMap(get(d.doc, "spec", "selector", "matchLabels")); !labelsMatch(sel, podLabels) {
+			report.add(SeverityError, d.file, 0, "Deployment %s selector does not match its pod template labels", d.name())
+		}
+		checkPodSpec(report, d, healthPaths, svc)
+
+		for _, s := range byKind["Service"] {
+			if sel := stringMap(get(s.doc, "spec", "selector")); len(sel) > 0 && !labelsMatch(sel, podLabels) {
+				report.add(SeverityError, s.file, 0, "Service %s selector does not match Deployment %s pods", s.name(), d.name())
+			}
+		}
+		for _, p := range byKind["PodDisruptionBudget"] {
+			if sel := stringMap(get(p.doc, "spec", "selector", "matchLabels")); !labelsMatch(sel, podLabels) {
+				report.add(SeverityError, p.file, 0, "PodDisruptionBudget %s selector does not match Deployment %s pods", p.name(), d.name())
+			}
+		}
+		for _, h := range byKind["HorizontalPodAutoscaler"] {
+			if target := str(h.doc, "spec", "scaleTargetRef", "name"); target != d.name() {
+				report.add(SeverityError, h.file, 0, "HorizontalPodAutoscaler %s targets %q, not Deployment %s", h.name(), target, d.name())
+			}
+		}
+	}
+
+	for _, np := range byKind["NetworkPolicy"] {
+		checkNetworkPolicy(report, np, svc)
+	}
+	return report
+}
+
+// checkKustomizations verifies the base lists every manifest and each overlay builds on the base
+func checkKustomizations(report *Report, kustomizations map[string]map[string]any, baseFiles map[string]bool) {
+	baseK, ok := kustomizations[K8sBaseDir]
+	if !ok {
+		report.add(SeverityError, K8sBaseDir+"/kustomization.yaml", 0, "base has no kustomization.yaml")
+	} else {
+		listed := map[string]bool{}
+		for _, r := range stringSlice(baseK["resources"]) {
+			listed[path.Clean(r)] = true
+			if !baseFiles[path.Clean(r)] {
+				report.add(SeverityError, K8sBaseDir+"/kustomization.yaml", 0, "resource %s does not exist", r)
+			}
+		}
+		files := make([]string, 0, len(baseFiles))
+		for f := range baseFiles {
+			files = append(files, f)
+		}
+		sort.Strings(files)
+		for _, f := range files {
+			if !listed[f] {
+				report.add(SeverityWarning, K8sBaseDir+"/kustomization.yaml", 0, "manifest %s is not listed in resources", f)
+			}
+		}
+	}
+
+	overlays := 0
+	dirs := make([]string, 0, len(kustomizations))
+	for dir := range kustomizations {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if !strings.HasPrefix(dir, K8sOverlaysDir+"/") {
+			continue
+		}
+		overlays++
+		refsBase := false
+		for _, r := range stringSlice(kustomizations[dir]["resources"]) {
+			if path.Join(dir, r) == K8sBaseDir {
+				refsBase = true
+			}
+		}
+		if !refsBase {
+			report.add(SeverityError, dir+"/kustomization.yaml", 0, "overlay does not include %s in resources", K8sBaseDir)
+		}
+	}
+	if overlays == 0 {
+		report.add(SeverityWarning, K8sOverlaysDir, 0, "no environment overlays were generated")
+	}
+}
+
+// checkPodSpec checks probes, resources, security context and secret handling of a Deployment
+func checkPodSpec(report *Report, d k8sObject, healthPaths map[string]bool, svc *config.ServiceDefinition) {
+	podSpec, _ := get(d.doc, "spec", "template", "spec").(map[string]any)
+	containers, _ := podSpec["containers"].([]any)
+	podNonRoot, _ := get(podSpec, "securityContext", "runAsNonRoot").(bool)
+
+	for _, c := range containers {
+		cm, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		where := fmt.Sprintf("Deployment %s container %s", d.name(), str(cm, "name"))
+
+		for _, probe := range []string{"readinessProbe", "livenessProbe"} {
+			p, ok := cm[probe].(map[string]any)
+			if !ok {
+				report.add(SeverityError, d.file, 0, "%s has no %s", where, probe)
+				continue
+			}
+			probePath := str(p, "httpGet", "path")
+			switch {
+			case probePath == "":
+			case len(healthPaths) == 0:
+				report.add(SeverityWarning, d.file, 0, "%s %s path %s cannot be checked: no health route found in the generated code", where, probe, probePath)
+			case !healthPaths[NormalizePath(probePath)]:
+				report.add(SeverityError, d.file, 0, "%s %s path %s is not a registered route", where, probe, probePath)
+			}
+			if _, isGRPC := p["grpc"]; isGRPC && !svc.Exposes(config.InterfaceGRPC) {
+				report.add(SeverityError, d.file, 0, "%s %s uses a gRPC probe but the service exposes no gRPC interface", where, probe)
+			}
+		}
+
+		requests := stringMapAny(get(cm, "resources", "requests"))
+		for _, r := range []string{"cpu", "memory"} {
+			if _, ok := requests[r]; !ok {
+				report.add(SeverityError, d.file, 0, "%s declares no %s request", where, r)
+			}
+		}
+		if _, ok := stringMapAny(get(cm, "resources", "limits"))["memory"]; !ok {
+			report.add(SeverityWarning, d.file, 0, "%s declares no memory limit", where)
+		}
+
+		nonRoot, ok := get(cm, "securityContext", "runAsNonRoot").(bool)
+		if !(ok && nonRoot) && !podNonRoot {
+			report.add(SeverityWarning, d.file, 0, "%s does not set runAsNonRoot", where)
+		}
+
+		env, _ := cm["env"].([]any)
+		for _, e := range env {
+			em, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			if _, literal := em["value"]; literal && secretNameRe.MatchString(str(em, "name")) {
+				report.add(SeverityError, d.file, 0, "%s sets %s inline; reference it with secretKeyRef", where, str(em, "name"))
+			}
+		}
+	}
+}
+
+// checkNetworkPolicy requires an egress rule for every declared datastore or broker port
+func checkNetworkPolicy(report *Report, np k8sObject, svc *config.ServiceDefinition) {
+	types := stringSlice(get(np.doc, "spec", "policyTypes"))
+	if !contains(types, "Egress") {
+		report.add(SeverityWarning, np.file, 0, "NetworkPolicy %s does not restrict egress", np.name())
+		return
+	}
+
+	ports := map[int]bool{}
+	egress, _ := get(np.doc, "spec", "egress").([]any)
+	for _, rule := range egress {
+		rm, _ := rule.(map[string]any)
+		rulePorts, _ := rm["ports"].([]any)
+		if len(rulePorts) == 0 {
+			// a rule without ports allows all ports to its peers
+			ports[0] = true
+		}
+		for _, p := range rulePorts {
+			if n, ok := get(p, "port").(int); ok {
+				ports[n] = true
+			}
+		}
+	}
+	if !ports[53] && !ports[0] {
+		report.add(SeverityWarning, np.file, 0, "NetworkPolicy %s does not allow DNS egress (port 53)", np.name())
+	}
+	for _, in := range svc.ParsedIntegrations() {
+		port := in.DefaultPort()
+		if port == 0 || ports[port] || ports[0] {
+			continue
+		}
+		report.add(SeverityError, np.file, 0, "NetworkPolicy %s allows no egress to %s (port %d)", np.name(), in.Name, port)
+	}
+}
+
+// healthRoutes returns the normalized paths of operational routes registered in generated Go code
+func healthRoutes(artifacts []agents.Artifact) map[string]bool {
+	paths := map[string]bool{}
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		routes, err := ExtractRoutes(a.Filename, a.Content)
+		if err != nil {
+			continue
+		}
+		for _, r := range routes {
+			if isInfraPath(r.Path) {
+				paths[NormalizePath(r.Path)] = true
+			}
+		}
+	}
+	return paths
+}
+
+func decodeYAMLDocuments(src string) ([]map[string]any, error) {
+	var docs []map[string]any
+	dec := yaml.NewDecoder(strings.NewReader(src))
+	for {
+		var doc map[string]any
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+}
+
+func isYAMLFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+// get walks nested maps by key and returns nil when any step is missing
+func get(v any, keys ...string) any {
+	for _, k := range keys {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[k]
+	}
+	return v
+}
+
+func str(v any, keys ...string) string {
+	s, _ := get(v, keys...).(string)
+	return s
+}
+
+func stringMapAny(v any) map[string]any {
+	m, _ := v.(map[string]any)
+	return m
+}
+
+func stringMap(v any) map[string]string {
+	out := map[string]string{}
+	for k, val := range stringMapAny(v) {
+		out[k] = fmt.Sprint(val)
+	}
+	return out
+}
+
+func stringSlice(v any) []string {
+	arr, _ := v.([]any)
+	out := make([]string, 0, len(arr))
+	for _, x := range arr {
+		if s, ok := x.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// labelsMatch reports whether a non-empty selector is satisfied by labels
+func labelsMatch(selector, labels map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package verify
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+var (
+	// k6RequestRe matches http.get(url, ...), http.post(url, ...) and http.request("METHOD", url, ...)
+	k6RequestRe = regexp.MustCompile("http\\.(get|post|put|patch|del|head|request)\\(\\s*(?:['\"](\\w+)['\"]\\s*,\\s*)?([`'\"])([^`'\"]*)[`'\"]")
+
+	k6TemplateExpr    = regexp.MustCompile(`\$\{[^}]*\}`)
+	k6TaggedThreshold = regexp.MustCompile(`['"]?http_req_(?:duration|failed)\{[^}]+\}['"]?\s*:`)
+)
+
+// CheckLoadTests verifies the k6 scripts: thresholds and a ramp-up profile in
+// every script, per-endpoint thresholds, an Authorization header, and a
+// request for every endpoint declared in api/openapi.yaml
+func CheckLoadTests(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Load tests"}
+
+	var scripts []agents.Artifact
+	for _, a := range artifacts {
+		if strings.Contains(a.Filename, agents.LoadTestDir) && strings.HasSuffix(a.Filename, ".js") {
+			scripts = append(scripts, a)
+		}
+	}
+	if len(scripts) == 0 {
+		report.add(SeverityError, agents.LoadTestDir, 0, "no k6 scripts were generated")
+		return report
+	}
+
+	type request struct{ method, path string }
+	var requests []request
+	tagged, authorized := false, false
+	for _, s := range scripts {
+		if strings.Contains(s.Content, "export const options") || strings.Contains(s.Content, "export let options") {
+			if !strings.Contains(s.Content, "thresholds") {
+				report.add(SeverityError, s.Filename, 0, "options declare no thresholds")
+			}
+			if !strings.Contains(s.Content, "stages") && !strings.Contains(s.Content, "ramping-") {
+				report.add(SeverityWarning, s.Filename, 0, "no ramp-up profile (stages or a ramping executor)")
+			}
+		}
+		tagged = tagged || k6TaggedThreshold.MatchString(s.Content)
+		authorized = authorized || strings.Contains(s.Content, "Authorization")
+
+		for _, m := range k6RequestRe.FindAllStringSubmatch(s.Content, -1) {
+			method := strings.ToUpper(m[1])
+			switch method {
+			case "DEL":
+				method = "DELETE"
+			case "REQUEST":
+				method = strings.ToUpper(m[2])
+			}
+			requests = append(requests, request{method, k6Path(m[4])})
+		}
+	}
+	if !tagged {
+		report.add(SeverityWarning, agents.LoadTestDir, 0, "no per-endpoint thresholds (http_req_duration{name:...})")
+	}
+	if !authorized {
+		report.add(SeverityWarning, agents.LoadTestDir, 0, "no script sends an Authorization header")
+	}
+
+	art, ok := findArtifact(artifacts, OpenAPIPath)
+	if !ok {
+		return report
+	}
+	doc, err := spec.ParseOpenAPI([]byte(art.Content))
+	if err != nil {
+		return report // reported by the OpenAPI check
+	}
+	bases := append([]string{""}, doc.BasePaths()...)
+	var missing []string
+	for _, ep := range doc.Endpoints() {
+		if isInfraPath(ep.Path) {
+			continue
+		}
+		covered := false
+		for _, r := range requests {
+			if r.method == ep.Method && routeMatches(r.path, NormalizePath(ep.Path), bases) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			missing = append(missing, fmt.Sprintf("%s %s", ep.Method, ep.Path))
+		}
+	}
+	sort.Strings(missing)
+	for _, m := range missing {
+		report.add(SeverityWarning, agents.LoadTestDir, 0, "endpoint %s has no load test", m)
+	}
+	return report
+}
+
+// k6Path reduces a k6 URL expression such as `${BASE_URL}/orders/${id}?x=1`
+// to a normalized path
+func k6Path(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		url = url[:i]
+	}
+	if i := strings.Index(url, "://"); i >= 0 {
+		url = url[i+3:]
+		if j := strings.Index(url, "/"); j >= 0 {
+			url = url[j:]
+		} else {
+			url = "/"
+		}
+	}
+	// A leading template expression is the base URL
+	if strings.HasPrefix(url, "${") {
+		if i := strings.Index(url, "}"); i >= 0 {
+			url = url[i+1:]
+		}
+	}
+	return NormalizePath(k6TemplateExpr.ReplaceAllString(url, "{}"))
+}
@@ -0,0 +1,226 @@
+package verify
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// migrationNameRe matches NNN_description_up.sql and golang-migrate's NNN_description.up.sql
+var migrationNameRe = regexp.MustCompile(`^(\d+)_(.+?)[._](up|down)\.sql$`)
+
+// migration is a numbered up/down pair
+type migration struct {
+	version  int
+	up, down *agents.Artifact
+}
+
+// CheckMigrations analyses the SQL migrations without a database: every up
+// has a down that restores the previous schema, versions are contiguous, and
+// up migrations avoid changes that fail or lock on a populated table
+func CheckMigrations(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Migrations"}
+
+	migrations := parseMigrations(report, artifacts)
+	if len(migrations) == 0 {
+		report.add(SeverityError, agents.MigrationDir, 0, "no migrations were generated")
+		return report
+	}
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version != migrations[i-1].version+1 {
+			report.add(SeverityError, agents.MigrationDir, 0, "migration numbering jumps from %d to %d", migrations[i-1].version, migrations[i].version)
+		}
+	}
+
+	schema := spec.NewSQLSchema()
+	for _, mig := range migrations {
+		if mig.up == nil {
+			report.add(SeverityError, mig.down.Filename, 0, "down migration has no matching up migration")
+			continue
+		}
+		before := schema.Clone()
+		for _, stmt := range spec.SplitSQL(mig.up.Content) {
+			checkMigrationStatement(report, mig.up.Filename, before, schema, stmt)
+		}
+		if mig.down == nil {
+			report.add(SeverityError, mig.up.Filename, 0, "up migration has no matching down migration")
+			continue
+		}
+		reverted := schema.Clone()
+		for _, stmt := range spec.SplitSQL(mig.down.Content) {
+			if err := reverted.Apply(stmt); err != nil {
+				report.add(SeverityError, mig.down.Filename, stmt.Line, "%v", err)
+			}
+		}
+		for _, d := range before.Diff(reverted) {
+			report.add(SeverityError, mig.down.Filename, 0, "does not reverse the up migration: %s", d)
+		}
+	}
+	return report
+}
+
+// parseMigrations pairs the up and down files under the migration directory
+// by version, in version order, reporting files it cannot pair
+func parseMigrations(report *Report, artifacts []agents.Artifact) []*migration {
+	byVersion := map[int]*migration{}
+	for _, a := range sqlFilesIn(artifacts, agents.MigrationDir) {
+		m := migrationNameRe.FindStringSubmatch(path.Base(a.Filename))
+		if m == nil {
+			report.add(SeverityWarning, a.Filename, 0, "not named <NNN>_<description>_up.sql or _down.sql; migration tools will skip it")
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		mig := byVersion[version]
+		if mig == nil {
+			mig = &migration{version: version}
+			byVersion[version] = mig
+		}
+		slot := &mig.up
+		if m[3] == "down" {
+			slot = &mig.down
+		}
+		if *slot != nil {
+			report.add(SeverityError, a.Filename, 0, "duplicate %s migration for version %s (also %s)", m[3], m[1], (*slot).Filename)
+			continue
+		}
+		art := a
+		*slot = &art
+	}
+
+	migrations := make([]*migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		migrations = append(migrations, mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations
+}
+
+// checkMigrationStatement applies one up statement to schema and reports
+// changes to tables that existed before the migration which fail or lock
+// when the table holds data
+func checkMigrationStatement(report *Report, file string, before, schema *spec.SQLSchema, stmt spec.SQLStatement) {
+	if idx, ok := spec.ParseCreateIndex(stmt); ok {
+		if _, existed := before.Tables[idx.Table]; existed && !idx.Concurrently {
+			report.add(SeverityWarning, file, stmt.Line, "creates index %s on existing table %s without CONCURRENTLY; writes block until it is built", idx.Name, idx.Table)
+		}
+	}
+
+	prev := schema.Clone()
+	if err := schema.Apply(stmt); err != nil {
+		report.add(SeverityError, file, stmt.Line, "%v", err)
+		return
+	}
+	var names []string
+	for name := range schema.Tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t := schema.Tables[name]
+		old, existed := before.Tables[name]
+		pt := prev.Tables[name]
+		if !existed || pt == nil {
+			continue
+		}
+		for _, c := range t.Columns {
+			pc := pt.Column(c.Name)
+			if pc == nil {
+				if old.Column(c.Name) == nil && c.NotNull && !c.HasDefault {
+					report.add(SeverityError, file, stmt.Line, "adds NOT NULL column %s.%s without a default; fails once the table has rows", name, c.Name)
+				}
+				continue
+			}
+			switch {
+			case c.NotNull && !pc.NotNull:
+				report.add(SeverityWarning, file, stmt.Line, "sets %s.%s NOT NULL; scans the table under lock and fails if any row is NULL", name, c.Name)
+			case c.Type != pc.Type && narrowsType(pc.Type, c.Type):
+				report.add(SeverityWarning, file, stmt.Line, "narrows %s.%s from %s to %s; existing values may not fit", name, c.Name, pc.Type, c.Type)
+			}
+		}
+	}
+}
+
+// sqlTypeFamilies groups type names whose values convert without loss to a
+// wider member of the same family; rank orders the members by width
+var sqlTypeFamilies = map[string]struct {
+	family string
+	rank   int
+}{
+	"smallint":                    {"int", 1},
+	"int2":                        {"int", 1},
+	"smallserial":                 {"int", 1},
+	"integer":                     {"int", 2},
+	"int":                         {"int", 2},
+	"int4":                        {"int", 2},
+	"serial":                      {"int", 2},
+	"bigint":                      {"int", 3},
+	"int8":                        {"int", 3},
+	"bigserial":                   {"int", 3},
+	"real":                        {"float", 1},
+	"float4":                      {"float", 1},
+	"double precision":            {"float", 2},
+	"float8":                      {"float", 2},
+	"float":                       {"float", 2},
+	"numeric":                     {"numeric", 0},
+	"decimal":                     {"numeric", 0},
+	"char":                        {"string", 0},
+	"character":                   {"string", 0},
+	"varchar":                     {"string", 0},
+	"character varying":           {"string", 0},
+	"text":                        {"string", 0},
+	"date":                        {"time", 1},
+	"timestamp":                   {"time", 2},
+	"timestamp without time zone": {"time", 2},
+	"timestamptz":                 {"time", 3},
+	"timestamp with time zone":    {"time", 3},
+}
+
+// narrowsType reports whether converting a column from one type to another can lose or reject existing values
+func narrowsType(from, to string) bool {
+	fb, fp := splitSQLType(from)
+	tb, tp := splitSQLType(to)
+	ff, fok := sqlTypeFamilies[fb]
+	tf, tok := sqlTypeFamilies[tb]
+	if !fok || !tok {
+		return false
+	}
+	if ff.family != tf.family {
+		unbounded := len(tp) == 0 && (tf.family == "string" || tf.family == "numeric" && ff.family == "int")
+		return !unbounded
+	}
+	switch ff.family {
+	case "string":
+		return len(tp) > 0 && (len(fp) == 0 || fb == "text" || tp[0] < fp[0])
+	case "numeric":
+		if len(tp) == 0 {
+			return false
+		}
+		if len(fp) == 0 {
+			return true
+		}
+		for i := range tp {
+			if i < len(fp) && tp[i] < fp[i] {
+				return true
+			}
+		}
+		return false
+	}
+	return tf.rank < ff.rank
+}
+
+// splitSQLType splits "varchar(255)" into "varchar" and [255]
+func splitSQLType(t string) (string, []int) {
+	base, args, _ := strings.Cut(t, "(")
+	var params []int
+	for _, a := range strings.Split(strings.TrimSuffix(args, ")"), ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(a)); err == nil {
+			params = append(params, n)
+		}
+	}
+	return strings.TrimSpace(base), params
+}
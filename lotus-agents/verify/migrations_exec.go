@@ -0,0 +1,354 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/sandbox"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+const (
+	// migrationTimeout bounds starting a database and applying every migration
+	migrationTimeout = 3 * time.Minute
+
+	// postgresImage is the image started when the database runs in docker
+	postgresImage = "postgres:16-alpine"
+
+	// migrationRole owns the scratch database the migrations run in; it is
+	// not a superuser, so COPY ... PROGRAM and untrusted extensions fail
+	migrationRole = "migrator"
+)
+
+// migrationLimits bound a sandboxed migration run
+var migrationLimits = sandbox.Limits{
+	CPUTime: 2 * time.Minute,
+	Memory:  1 << 30,
+	Wall:    migrationTimeout,
+}
+
+var (
+	psqlErrorRe   = regexp.MustCompile(`psql:[^:\n]*:(\d+):\s*ERROR:\s*(.+)`)
+	sqliteErrorRe = regexp.MustCompile(`near line (\d+):\s*(.+)`)
+	stepMarkerRe  = regexp.MustCompile(`(?m)^-- step (\d+)$`)
+)
+
+// postgresScript starts a private cluster in the scratch dir ($1), creates
+// an unprivileged owner for the migration database and applies steps/*.sql
+// in order, printing a marker before each. Exit status 2 means the cluster
+// did not start.
+const postgresScript = `set -u
+scratch=$1
+trap 'pg_ctl -D "$scratch/data" -m immediate stop >/dev/null 2>&1' EXIT
+{
+	initdb -D "$scratch/data" -U postgres -A trust --locale=C -E UTF8 --no-sync &&
+	pg_ctl -D "$scratch/data" -o "-k $scratch -c listen_addresses='' -c fsync=off -c dynamic_shared_memory_type=mmap" -l "$scratch/server.log" -w start &&
+	psql -h "$scratch" -U postgres -X -q -v ON_ERROR_STOP=1 -c "CREATE ROLE ` + migrationRole + ` LOGIN" -c "CREATE DATABASE migrate OWNER ` + migrationRole + `"
+} >"$scratch/setup.log" 2>&1 || { cat "$scratch/setup.log" "$scratch/server.log" 2>/dev/null; exit 2; }
+for f in steps/*.sql; do
+	echo "-- step $(basename "$f" .sql)"
+	psql -h "$scratch" -U ` + migrationRole + ` -d migrate -X -q -v ON_ERROR_STOP=1 -f "$f" 2>&1 || exit 1
+done
+`
+
+// sqliteScript applies steps/*.sql to a database in the scratch dir ($1) in
+// safe mode, which refuses ATTACH, extensions and dot-commands that write
+// files or run programs
+const sqliteScript = `set -u
+for f in steps/*.sql; do
+	echo "-- step $(basename "$f" .sql)"
+	sqlite3 -bail -safe "$1/migrate.db" <"$f" 2>&1 || exit 1
+done
+`
+
+// scratchDB is a throwaway database the migrations are applied to
+type scratchDB struct {
+	name   string
+	sqlite bool
+
+	// apply runs the scripts in order and stops at the first that fails,
+	// returning its index and what the database client printed for it. An
+	// error with index -1 means the database could not be used at all.
+	apply   func(ctx context.Context, scripts []string) (int, string, error)
+	cleanup func()
+}
+
+// ExecuteMigrations applies every up migration in order and then every down
+// migration in reverse order against a scratch database, and reports the
+// first statement that fails. Migrations are model-written, so they only run
+// as an unprivileged role in a disposable postgres:16-alpine container when
+// sandboxName is "docker", or otherwise through that sandbox.Runner in a
+// private initdb cluster or, failing that, sqlite3, whose failures are only
+// warnings. Scripts containing psql or sqlite3 meta-commands are rejected
+// without running anything.
+func ExecuteMigrations(ctx context.Context, sandboxName string, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Migration execution"}
+
+	migrations := parseMigrations(&Report{}, artifacts) // naming is reported by CheckMigrations
+	if len(migrations) == 0 {
+		return report
+	}
+
+	var steps []*agents.Artifact
+	for _, mig := range migrations {
+		if mig.up != nil {
+			steps = append(steps, mig.up)
+		}
+	}
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if migrations[i].down != nil {
+			steps = append(steps, migrations[i].down)
+		}
+	}
+	scripts := make([]string, len(steps))
+	for i, a := range steps {
+		for n, line := range strings.Split(a.Content, "\n") {
+			if l := strings.TrimSpace(line); strings.HasPrefix(l, ".") || strings.HasPrefix(l, `\`) {
+				report.add(SeverityError, a.Filename, n+1, "contains the client meta-command %q; migrations must be plain SQL", firstLine(l))
+			}
+		}
+		scripts[i] = a.Content
+	}
+	if len(report.Findings) > 0 {
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, migrationTimeout)
+	defer cancel()
+	db, err := openScratchDB(ctx, sandboxName)
+	if err != nil {
+		report.add(SeverityInfo, agents.MigrationDir, 0, "skipped: %v", err)
+		return report
+	}
+	defer db.cleanup()
+
+	i, out, err := db.apply(ctx, scripts)
+	switch {
+	case err == nil:
+		return report
+	case ctx.Err() != nil || errors.Is(err, sandbox.ErrTimeout):
+		file := agents.MigrationDir
+		if i >= 0 {
+			file = steps[i].Filename
+		}
+		report.add(SeverityError, file, 0, "timed out on %s after %s", db.name, migrationTimeout)
+		return report
+	case i < 0:
+		report.add(SeverityInfo, agents.MigrationDir, 0, "skipped: %s did not start: %s", db.name, lastLine(err.Error()+"\n"+out))
+		return report
+	}
+	a := steps[i]
+	line, msg := migrationError(out, err, db.sqlite)
+	stmt := failingStatement(a.Content, line)
+	if stmt.Text != "" {
+		msg += " in: " + firstLine(stmt.Text)
+	}
+	if db.sqlite {
+		report.add(SeverityWarning, a.Filename, stmt.Line, "fails on %s (may be PostgreSQL-only syntax): %s", db.name, msg)
+	} else {
+		report.add(SeverityError, a.Filename, stmt.Line, "fails on %s: %s", db.name, msg)
+	}
+	return report
+}
+
+// openScratchDB starts a disposable PostgreSQL container for the docker
+// sandbox, and otherwise prepares the runner sandboxName selects
+func openScratchDB(ctx context.Context, sandboxName string) (*scratchDB, error) {
+	runner, err := sandbox.New(sandboxName)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := runner.(*sandbox.Docker); ok {
+		return dockerPostgresDB(ctx)
+	}
+	return sandboxedDB(runner)
+}
+
+// dockerPostgresDB starts a PostgreSQL container without network access and
+// waits until it accepts connections from the unprivileged migration role
+func dockerPostgresDB(ctx context.Context) (*scratchDB, error) {
+	out, err := runWithInput(ctx, "", "docker", "run", "-d", "--rm",
+		"--network", "none", "--memory", fmt.Sprint(migrationLimits.Memory), "--pids-limit", "256",
+		"-e", "POSTGRES_HOST_AUTH_METHOD=trust", postgresImage)
+	if err != nil {
+		return nil, fmt.Errorf("docker run: %s", strings.TrimSpace(out))
+	}
+	id := strings.TrimSpace(out)
+	cleanup := func() { runWithInput(context.Background(), "", "docker", "rm", "-f", id) }
+
+	// The image's init scripts run on a socket-only server, so wait for TCP
+	for {
+		if _, err := runWithInput(ctx, "", "docker", "exec", id, "pg_isready", "-h", "127.0.0.1", "-U", "postgres"); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			cleanup()
+			return nil, fmt.Errorf("%s did not become ready", postgresImage)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+	setup := fmt.Sprintf("CREATE ROLE %s LOGIN;\nCREATE DATABASE migrate OWNER %s;\n", migrationRole, migrationRole)
+	if out, err := runWithInput(ctx, setup, "docker", "exec", "-i", id, "psql", "-h", "127.0.0.1", "-U", "postgres", "-X", "-q", "-v", "ON_ERROR_STOP=1", "-f", "-"); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("create the migration role: %s", strings.TrimSpace(out))
+	}
+	return &scratchDB{
+		name: "PostgreSQL (docker " + postgresImage + ")",
+		apply: func(ctx context.Context, scripts []string) (int, string, error) {
+			for i, script := range scripts {
+				out, err := runWithInput(ctx, script, "docker", "exec", "-i", id, "psql", "-h", "127.0.0.1", "-U", migrationRole, "-d", "migrate", "-X", "-q", "-v", "ON_ERROR_STOP=1", "-f", "-")
+				if err != nil {
+					return i, out, err
+				}
+			}
+			return 0, "", nil
+		},
+		cleanup: cleanup,
+	}, nil
+}
+
+// sandboxedDB applies the migrations in one runner invocation: to a private
+// PostgreSQL cluster when initdb, pg_ctl and psql are installed (initdb
+// refuses to run as root), otherwise to sqlite3
+func sandboxedDB(runner sandbox.Runner) (*scratchDB, error) {
+	script, name, sqlite := postgresScript, "PostgreSQL (local initdb, "+runner.Name()+" sandbox)", false
+	for _, tool := range []string{"initdb", "pg_ctl", "psql"} {
+		if _, err := exec.LookPath(tool); err != nil || os.Geteuid() == 0 {
+			script, name, sqlite = sqliteScript, "SQLite ("+runner.Name()+" sandbox)", true
+			break
+		}
+	}
+	if _, err := exec.LookPath("sqlite3"); sqlite && err != nil {
+		return nil, fmt.Errorf("no PostgreSQL (docker sandbox, or initdb/pg_ctl/psql as a non-root user) or sqlite3 available")
+	}
+
+	dir, err := os.MkdirTemp("", "lotus-migrate-")
+	if err != nil {
+		return nil, err
+	}
+	src, scratch := filepath.Join(dir, "src"), filepath.Join(dir, "scratch")
+	return &scratchDB{
+		name:   name,
+		sqlite: sqlite,
+		apply: func(ctx context.Context, scripts []string) (int, string, error) {
+			if err := writeMigrationSteps(src, scratch, script, scripts); err != nil {
+				return -1, "", err
+			}
+			cmd := sandbox.Command{
+				Args:     []string{"/bin/sh", "migrate.sh", scratch},
+				Dir:      src,
+				Env:      []string{"PATH=" + os.Getenv("PATH"), "HOME=" + scratch, "TMPDIR=" + scratch, "LC_ALL=C"},
+				Writable: []string{scratch},
+				Limits:   migrationLimits,
+			}
+			var out bytes.Buffer
+			err := runner.Run(ctx, cmd, &out, &out)
+			var exitErr *exec.ExitError
+			if err == nil {
+				return 0, "", nil
+			}
+			if errors.As(err, &exitErr) && exitErr.ExitCode() == 2 {
+				return -1, out.String(), err
+			}
+			markers := stepMarkerRe.FindAllStringSubmatchIndex(out.String(), -1)
+			if len(markers) == 0 {
+				return -1, out.String(), err
+			}
+			last := markers[len(markers)-1]
+			i, _ := strconv.Atoi(out.String()[last[2]:last[3]])
+			return i, out.String()[last[1]:], err
+		},
+		cleanup: func() {
+			// The process sandbox leaves a server running if the script was killed
+			if _, err := os.Stat(filepath.Join(scratch, "data", "postmaster.pid")); err == nil {
+				runWithInput(context.Background(), "", "pg_ctl", "-D", filepath.Join(scratch, "data"), "-m", "immediate", "stop")
+			}
+			os.RemoveAll(dir)
+		},
+	}, nil
+}
+
+// writeMigrationSteps writes the run script and the scripts as
+// steps/NNNN.sql to src, left read-only, and creates the scratch dir
+func writeMigrationSteps(src, scratch, script string, scripts []string) error {
+	if err := os.MkdirAll(filepath.Join(src, "steps"), 0755); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(scratch, 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(src, "migrate.sh"), []byte(script), 0444); err != nil {
+		return err
+	}
+	for i, s := range scripts {
+		if err := os.WriteFile(filepath.Join(src, "steps", fmt.Sprintf("%04d.sql", i)), []byte(s), 0444); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runWithInput runs a trusted command with input on stdin and returns its combined output
+func runWithInput(ctx context.Context, input, name string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+// migrationError extracts the line and message of the first error psql or sqlite3 printed
+func migrationError(out string, err error, sqlite bool) (int, string) {
+	re := psqlErrorRe
+	if sqlite {
+		re = sqliteErrorRe
+	}
+	if m := re.FindStringSubmatch(out); m != nil {
+		var line int
+		fmt.Sscan(m[1], &line)
+		return line, strings.TrimSpace(m[2])
+	}
+	if msg := firstLine(strings.TrimSpace(out)); msg != "" {
+		return 0, msg
+	}
+	return 0, err.Error()
+}
+
+// failingStatement returns the statement of a script that contains the given
+// line, or a zero statement when the line is unknown
+func failingStatement(script string, line int) spec.SQLStatement {
+	var found spec.SQLStatement
+	if line <= 0 {
+		return found
+	}
+	for _, stmt := range spec.SplitSQL(script) {
+		if stmt.Line > line && found.Text != "" {
+			break
+		}
+		found = stmt
+	}
+	return found
+}
+
+func firstLine(s string) string {
+	first, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(first)
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	return strings.TrimSpace(s[strings.LastIndex(s, "\n")+1:])
+}
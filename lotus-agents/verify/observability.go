@@ -0,0 +1,298 @@
+package verify
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+// Locations the Observability agent writes to
+const (
+	ObservabilityDir = "internal/infrastructure/observability"
+	DashboardDir     = "deploy/grafana"
+)
+
+// otelInstruments are the OTel metric API constructors whose first argument is the instrument name
+var otelInstruments = map[string]bool{
+	"Int64Counter": true, "Float64Counter": true,
+	"Int64UpDownCounter": true, "Float64UpDownCounter": true,
+	"Int64Histogram": true, "Float64Histogram": true,
+	"Int64Gauge": true, "Float64Gauge": true,
+	"Int64ObservableCounter": true, "Float64ObservableCounter": true,
+	"Int64ObservableUpDownCounter": true, "Float64ObservableUpDownCounter": true,
+	"Int64ObservableGauge": true, "Float64ObservableGauge": true,
+}
+
+// runtimeMetricPrefixes are exported by client libraries and exporters without explicit registration
+var runtimeMetricPrefixes = []string{"go_", "process_", "promhttp_", "target_info", "up"}
+
+var (
+	promSelectorRe = regexp.MustCompile(`([a-zA-Z_:][a-zA-Z0-9_:]*)\s*[{\[]`)
+	promSuffixes   = []string{"_bucket", "_sum", "_count", "_total", "_created", "_seconds", "_milliseconds", "_bytes", "_ratio"}
+)
+
+// CheckObservability verifies that main.go bootstraps telemetry, that Kafka
+// producers and consumers propagate trace context, that every metric the
+// Grafana dashboard queries is registered by the generated code, and that
+// the service logs through log/slog.
+func CheckObservability(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Observability"}
+
+	if main, ok := findArtifact(artifacts, "cmd/server/main.go"); ok {
+		if !callsImported(main, "/observability", "Setup") {
+			report.add(SeverityError, main.Filename, 0, "main.go does not call observability.Setup")
+		}
+	}
+
+	checkKafkaPropagation(report, artifacts, "internal/infrastructure/kafka/producer/", "Inject", "inject trace context into message headers")
+	checkKafkaPropagation(report, artifacts, "internal/infrastructure/kafka/consumer/", "Extract", "extract trace context from message headers")
+
+	registered := registeredMetrics(artifacts)
+	dashboards := 0
+	for _, a := range artifacts {
+		if !strings.HasPrefix(a.Filename, DashboardDir+"/") || path.Ext(a.Filename) != ".json" {
+			continue
+		}
+		dashboards++
+		checkDashboard(report, a, registered)
+	}
+	if dashboards == 0 {
+		report.add(SeverityError, DashboardDir, 0, "no Grafana dashboard was generated")
+	}
+
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".go") || strings.HasSuffix(a.Filename, "_test.go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, a.Filename, a.Content, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			if imp.Path.Value == `"log"` {
+				report.add(SeverityWarning, a.Filename, fset.Position(imp.Pos()).Line, "imports log; use log/slog so records carry trace and correlation IDs")
+			}
+		}
+	}
+	return report
+}
+
+// checkKafkaPropagation requires at least one propagation call in the files under dir
+func checkKafkaPropagation(report *Report, artifacts []agents.Artifact, dir, call, what string) {
+	var files []string
+	for _, a := range artifacts {
+		if !strings.HasPrefix(a.Filename, dir) || !strings.HasSuffix(a.Filename, ".go") || strings.HasSuffix(a.Filename, "_test.go") {
+			continue
+		}
+		files = append(files, a.Filename)
+		if callsContaining(a, call) {
+			return
+		}
+	}
+	if len(files) > 0 {
+		report.add(SeverityError, strings.TrimSuffix(dir, "/"), 0, "no file in %s calls %s; Kafka messages must %s", strings.TrimSuffix(dir, "/"), call, what)
+	}
+}
+
+// callsImported reports whether the file calls fn on a package whose import path ends with suffix
+func callsImported(a agents.Artifact, suffix, fn string) bool {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, a.Filename, a.Content, 0)
+	if err != nil {
+		return false
+	}
+	name := ""
+	for _, imp := range file.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		if strings.HasSuffix(p, suffix) {
+			name = path.Base(p)
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+		}
+	}
+	if name == "" {
+		return false
+	}
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == fn {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == name {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// callsContaining reports whether the file calls any function or method whose name contains s
+func callsContaining(a agents.Artifact, s string) bool {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, a.Filename, a.Content, 0)
+	if err != nil {
+		return strings.Contains(a.Content, s+"(")
+	}
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return !found
+		}
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			found = found || strings.Contains(fn.Name, s)
+		case *ast.SelectorExpr:
+			found = found || strings.Contains(fn.Sel.Name, s)
+		}
+		return !found
+	})
+	return found
+}
+
+// registeredMetrics collects metric names declared through prometheus *Opts
+// literals or OTel meter instruments, normalized for comparison
+func registeredMetrics(artifacts []agents.Artifact) map[string]bool {
+	names := map[string]bool{}
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, a.Filename, a.Content, 0)
+		if err != nil {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.CompositeLit:
+				parts := map[string]string{}
+				for _, elt := range n.Elts {
+					kv, ok := elt.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					key, ok := kv.Key.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					if v, ok := stringLit(kv.Value); ok {
+						parts[key.Name] = v
+					}
+				}
+				if parts["Name"] != "" && (parts["Help"] != "" || parts["Namespace"] != "" || parts["Subsystem"] != "") {
+					var full []string
+					for _, k := range []string{"Namespace", "Subsystem", "Name"} {
+						if parts[k] != "" {
+							full = append(full, parts[k])
+						}
+					}
+					names[normalizeMetric(strings.Join(full, "_"))] = true
+				}
+			case *ast.CallExpr:
+				if sel, ok := n.Fun.(*ast.SelectorExpr); ok && otelInstruments[sel.Sel.Name] && len(n.Args) > 0 {
+					if v, ok := stringLit(n.Args[0]); ok {
+						names[normalizeMetric(v)] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	return names
+}
+
+// checkDashboard parses a Grafana dashboard and checks the metrics its queries use
+func checkDashboard(report *Report, a agents.Artifact, registered map[string]bool) {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(a.Content), &doc); err != nil {
+		report.add(SeverityError, a.Filename, 0, "dashboard is not valid JSON: %v", err)
+		return
+	}
+	if panels, _ := doc["panels"].([]any); len(panels) == 0 {
+		report.add(SeverityError, a.Filename, 0, "dashboard has no panels")
+		return
+	}
+
+	var exprs []string
+	collectExprs(doc, &exprs)
+	if len(exprs) == 0 {
+		report.add(SeverityError, a.Filename, 0, "dashboard panels have no queries")
+		return
+	}
+	if len(registered) == 0 {
+		report.add(SeverityWarning, a.Filename, 0, "no metric registrations found in the generated code; dashboard queries not checked")
+		return
+	}
+
+	unknown := map[string]bool{}
+	for _, expr := range exprs {
+		for _, m := range promSelectorRe.FindAllStringSubmatch(expr, -1) {
+			metric := m[1]
+			if isRuntimeMetric(metric) || registered[normalizeMetric(metric)] {
+				continue
+			}
+			unknown[metric] = true
+		}
+	}
+	missing := make([]string, 0, len(unknown))
+	for m := range unknown {
+		missing = append(missing, m)
+	}
+	sort.Strings(missing)
+	for _, m := range missing {
+		report.add(SeverityError, a.Filename, 0, "dashboard queries metric %s, which no generated code registers", m)
+	}
+}
+
+// collectExprs gathers every "expr" string in a dashboard, including nested rows
+func collectExprs(v any, out *[]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if s, ok := child.(string); ok && k == "expr" {
+				*out = append(*out, s)
+				continue
+			}
+			collectExprs(child, out)
+		}
+	case []any:
+		for _, child := range v {
+			collectExprs(child, out)
+		}
+	}
+}
+
+// normalizeMetric maps OTel instrument names and Prometheus series names to
+// a common base name by converting separators and dropping type/unit suffixes
+func normalizeMetric(name string) string {
+	name = strings.NewReplacer(".", "_", "-", "_").Replace(name)
+	for stripped := true; stripped; {
+		stripped = false
+		for _, s := range promSuffixes {
+			if strings.HasSuffix(name, s) && len(name) > len(s) {
+				name = strings.TrimSuffix(name, s)
+				stripped = true
+			}
+		}
+	}
+	return name
+}
+
+func isRuntimeMetric(name string) bool {
+	for _, p := range runtimeMetricPrefixes {
+		if name == p || strings.HasPrefix(name, p) && strings.HasSuffix(p, "_") {
+			return true
+		}
+	}
+	return false
+}
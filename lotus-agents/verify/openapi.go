@@ -0,0 +1,230 @@
+package verify
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// OpenAPIPath is where the OpenAPI agent writes the service contract
+const OpenAPIPath = "api/openapi.yaml"
+
+var specPathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// CheckOpenAPI parses the generated api/openapi.yaml, validates its structure,
+// checks that every ServiceDefinition operation maps to at least one path and
+// cross-checks the routes registered in router.go against the spec.
+func CheckOpenAPI(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "OpenAPI contract"}
+
+	art, ok := findArtifact(artifacts, OpenAPIPath)
+	if !ok {
+		report.add(SeverityError, OpenAPIPath, 0, "no OpenAPI document was generated")
+		return report
+	}
+	doc, err := spec.ParseOpenAPI([]byte(art.Content))
+	if err != nil {
+		report.add(SeverityError, art.Filename, 0, "%v", err)
+		return report
+	}
+
+	validateDocument(report, art.Filename, doc)
+	checkOperationCoverage(report, art.Filename, svc, doc)
+	checkRouterConformance(report, doc, artifacts)
+	return report
+}
+
+func validateDocument(report *Report, file string, doc *spec.OpenAPI) {
+	if !strings.HasPrefix(doc.OpenAPI, "3.1") {
+		report.add(SeverityWarning, file, 0, "openapi version is %q, expected 3.1.x", doc.OpenAPI)
+	}
+	if doc.Info.Title == "" || doc.Info.Version == "" {
+		report.add(SeverityError, file, 0, "info.title and info.version are required")
+	}
+	if len(doc.Paths) == 0 {
+		report.add(SeverityError, file, 0, "document declares no paths")
+	}
+
+	opIDs := map[string]string{}
+	for _, ep := range doc.Endpoints() {
+		where := ep.Method + " " + ep.Path
+		op := ep.Operation
+		if op.OperationID == "" {
+			report.add(SeverityWarning, file, 0, "%s has no operationId", where)
+		} else if prev, dup := opIDs[op.OperationID]; dup {
+			report.add(SeverityError, file, 0, "operationId %q is used by both %s and %s", op.OperationID, prev, where)
+		} else {
+			opIDs[op.OperationID] = where
+		}
+		if len(op.Responses) == 0 {
+			report.add(SeverityError, file, 0, "%s declares no responses", where)
+		}
+
+		for _, p := range append(doc.Paths[ep.Path].Parameters, op.Parameters...) {
+			if _, ok := doc.ResolveParameter(p); !ok && p.Ref != "" {
+				report.add(SeverityError, file, 0, "%s: unresolved parameter reference %q", where, p.Ref)
+			}
+		}
+		declared := map[string]bool{}
+		params := doc.Parameters(ep)
+		for _, p := range params {
+			if p.In == "path" {
+				declared[p.Name] = true
+			}
+		}
+		for _, m := range specPathParam.FindAllStringSubmatch(ep.Path, -1) {
+			if !declared[m[1]] {
+				report.add(SeverityError, file, 0, "%s: path parameter {%s} is not declared", where, m[1])
+			}
+		}
+
+		for _, s := range operationSchemas(op, params) {
+			checkRefs(report, file, where, doc, s)
+		}
+	}
+
+	for name, s := range doc.Components.Schemas {
+		checkRefs(report, file, "components.schemas."+name, doc, s)
+	}
+}
+
+func operationSchemas(op *spec.Operation, params []spec.Parameter) []*spec.Schema {
+	var schemas []*spec.Schema
+	for _, p := range params {
+		schemas = append(schemas, p.Schema)
+	}
+	if op.RequestBody != nil {
+		for _, mt := range op.RequestBody.Content {
+			schemas = append(schemas, mt.Schema)
+		}
+	}
+	for _, r := range op.Responses {
+		for _, mt := range r.Content {
+			schemas = append(schemas, mt.Schema)
+		}
+	}
+	return schemas
+}
+
+// checkRefs reports $ref values that do not resolve to a component schema
+func checkRefs(report *Report, file, where string, doc *spec.OpenAPI, s *spec.Schema) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		if _, ok := doc.ResolveSchema(s); !ok {
+			report.add(SeverityError, file, 0, "%s: unresolved reference %q", where, s.Ref)
+		}
+		return
+	}
+	for _, p := range s.Properties {
+		checkRefs(report, file, where, doc, p)
+	}
+	checkRefs(report, file, where, doc, s.Items)
+	for _, group := range [][]*spec.Schema{s.AllOf, s.OneOf, s.AnyOf} {
+		for _, sub := range group {
+			checkRefs(report, file, where, doc, sub)
+		}
+	}
+}
+
+// checkOperationCoverage ensures each ServiceDefinition operation is exposed by
+// at least one endpoint, matched via x-operation or the operation text itself
+func checkOperationCoverage(report *Report, file string, svc *config.ServiceDefinition, doc *spec.OpenAPI) {
+	eps := doc.Endpoints()
+	for _, want := range svc.Operations {
+		found := false
+		for _, ep := range eps {
+			op := ep.Operation
+			if strings.EqualFold(strings.TrimSpace(op.XOperation), strings.TrimSpace(want)) ||
+				containsFold(op.Summary, want) || containsFold(op.Description, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report.add(SeverityError, file, 0, "operation %q is not mapped to any path (set x-operation on the endpoint that implements it)", want)
+		}
+	}
+}
+
+func containsFold(s, substr string) bool {
+	return s != "" && strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// checkRouterConformance compares routes registered in router.go with the
+// endpoints declared in the spec
+func checkRouterConformance(report *Report, doc *spec.OpenAPI, artifacts []agents.Artifact) {
+	var routes []Route
+	var routerFiles []string
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, "/router.go") && a.Filename != "router.go" {
+			continue
+		}
+		rs, err := ExtractRoutes(a.Filename, a.Content)
+		if err != nil {
+			report.add(SeverityError, a.Filename, 0, "cannot parse router: %v", err)
+			continue
+		}
+		routes = append(routes, rs...)
+		routerFiles = append(routerFiles, a.Filename)
+	}
+	if len(routerFiles) == 0 {
+		report.add(SeverityWarning, "", 0, "no router.go artifact found; route conformance not checked")
+		return
+	}
+
+	bases := append([]string{""}, doc.BasePaths()...)
+	eps := doc.Endpoints()
+	routed := make([]bool, len(eps))
+
+	for _, r := range routes {
+		if isInfraPath(r.Path) {
+			continue
+		}
+		matched := false
+		for i, ep := range eps {
+			if r.Method != "" && r.Method != ep.Method {
+				continue
+			}
+			if routeMatches(NormalizePath(r.Path), NormalizePath(ep.Path), bases) {
+				routed[i] = true
+				matched = true
+			}
+		}
+		if !matched {
+			method := r.Method
+			if method == "" {
+				method = "*"
+			}
+			report.add(SeverityError, r.File, r.Line, "route %s %s is not declared in %s", method, r.Path, OpenAPIPath)
+		}
+	}
+
+	var missing []string
+	for i, ep := range eps {
+		if !routed[i] {
+			missing = append(missing, fmt.Sprintf("%s %s", ep.Method, ep.Path))
+		}
+	}
+	sort.Strings(missing)
+	for _, m := range missing {
+		report.add(SeverityWarning, strings.Join(routerFiles, ", "), 0, "spec endpoint %s has no matching route", m)
+	}
+}
+
+// routeMatches compares normalized paths, allowing the router to mount the
+// spec under one of the given base paths (the declared servers' paths) only
+func routeMatches(route, specPath string, bases []string) bool {
+	for _, b := range bases {
+		if route == NormalizePath(b+specPath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package verify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/postprocess"
+)
+
+var pactProviderRe = regexp.MustCompile(`Provider:\s*"([^"]+)"`)
+
+// CheckContractTests verifies the Pact tests: a consumer test per REST
+// integration, a message pact per consumed event, provider verification for
+// REST services, and the contract build tag on every test file
+func CheckContractTests(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Contract tests"}
+
+	var consumer, message, provider []agents.Artifact
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, agents.ContractTestDir) || !strings.HasSuffix(a.Filename, "_test.go") {
+			continue
+		}
+		if !strings.Contains(a.Content, "//go:build contract") {
+			report.add(SeverityWarning, a.Filename, 1, "missing //go:build contract constraint")
+		}
+		switch {
+		case strings.Contains(a.Filename, "/consumer/"):
+			consumer = append(consumer, a)
+		case strings.Contains(a.Filename, "/message/"):
+			message = append(message, a)
+		case strings.Contains(a.Filename, "/provider/"):
+			provider = append(provider, a)
+		}
+	}
+
+	providers := map[string]bool{}
+	for _, a := range consumer {
+		for _, m := range pactProviderRe.FindAllStringSubmatch(a.Content, -1) {
+			providers[m[1]] = true
+		}
+	}
+	for _, in := range svc.IntegrationsOfKind(config.KindREST) {
+		if !providers[in.Slug()] {
+			report.add(SeverityError, agents.ContractTestDir+"consumer/", 0, "no consumer pact with provider %q for integration %q", in.Slug(), in.Raw)
+		}
+	}
+
+	consumed, err := postprocess.ConsumedEvents(artifacts)
+	if err == nil {
+		for _, e := range consumed {
+			covered := false
+			for _, a := range message {
+				if strings.Contains(a.Content, e.Name) {
+					covered = true
+					break
+				}
+			}
+			if !covered {
+				report.add(SeverityError, e.File, e.Line, "consumed event %s has no message pact", e.Name)
+			}
+		}
+	}
+
+	if svc.Exposes(config.InterfaceREST) {
+		verified := false
+		for _, a := range provider {
+			if strings.Contains(a.Content, "VerifyProvider") {
+				verified = true
+			}
+		}
+		if !verified {
+			report.add(SeverityError, agents.ContractTestDir+"provider/", 0, "no provider verification (VerifyProvider) for the service's endpoints")
+		}
+	}
+	return report
+}
@@ -0,0 +1,240 @@
+package verify
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/postprocess"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// Directories whose type declarations must use planned names
+const (
+	useCaseDir    = "internal/application/usecase/"
+	portDir       = "internal/application/port/"
+	repositoryDir = "internal/domain/repository/"
+)
+
+var (
+	createTableRe = regexp.MustCompile(`(?i)\bcreate\s+table\s+(?:if\s+not\s+exists\s+)?([\w."]+)`)
+
+	// toolTables are created by migration tools rather than the service
+	toolTables = map[string]bool{"schema_migrations": true, "goose_db_version": true, "flyway_schema_history": true}
+
+	useCaseSuffixes = []string{"UseCase", "Usecase", "Interactor"}
+)
+
+// CheckPlanConformance compares the generated code with the Planner agent's
+// plan: use case, port and repository types, CREATE TABLE statements, event
+// structs and topics, and registered routes must all use planned names.
+func CheckPlanConformance(svc *config.ServiceDefinition, artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Plan conformance"}
+
+	art, ok := findArtifact(artifacts, agents.PlanPath)
+	if !ok {
+		report.add(SeverityError, agents.PlanPath, 0, "no design plan was generated")
+		return report
+	}
+	plan, err := spec.ParsePlan([]byte(art.Content))
+	if err != nil {
+		report.add(SeverityError, art.Filename, 0, "%v", err)
+		return report
+	}
+
+	for _, op := range svc.Operations {
+		found := false
+		for _, uc := range plan.UseCases {
+			if strings.EqualFold(strings.TrimSpace(uc.Operation), strings.TrimSpace(op)) {
+				found = true
+			}
+		}
+		if !found {
+			report.add(SeverityWarning, art.Filename, 0, "operation %q has no planned use case", op)
+		}
+	}
+
+	useCases := map[string]bool{}
+	for _, uc := range plan.UseCases {
+		useCases[uc.Name] = true
+	}
+	ports := map[string]bool{}
+	for _, p := range plan.Ports {
+		ports[p.Name] = true
+	}
+
+	definedUseCases := map[string]bool{}
+	sawUseCaseFiles := false
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".go") || strings.HasSuffix(a.Filename, "_test.go") {
+			continue
+		}
+		inUseCases := strings.Contains(a.Filename, useCaseDir)
+		inPorts := strings.Contains(a.Filename, portDir)
+		inRepos := strings.Contains(a.Filename, repositoryDir)
+		if !inUseCases && !inPorts && !inRepos {
+			continue
+		}
+		sawUseCaseFiles = sawUseCaseFiles || inUseCases
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, a.Filename, a.Content, 0)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, sp := range gen.Specs {
+				ts := sp.(*ast.TypeSpec)
+				name := plannedForm(ts.Name.Name)
+				line := fset.Position(ts.Pos()).Line
+				_, isInterface := ts.Type.(*ast.InterfaceType)
+				switch {
+				case inUseCases && hasUseCaseSuffix(name):
+					definedUseCases[name] = true
+					if !useCases[name] && !ports[name] {
+						report.add(SeverityError, a.Filename, line, "use case type %s is not in the plan", ts.Name.Name)
+					}
+				case (inPorts || inRepos) && isInterface:
+					if !ports[name] && !useCases[name] {
+						report.add(SeverityError, a.Filename, line, "interface %s is not a planned port", ts.Name.Name)
+					}
+				}
+			}
+		}
+	}
+	if sawUseCaseFiles {
+		for _, uc := range plan.UseCases {
+			if !definedUseCases[uc.Name] {
+				report.add(SeverityWarning, useCaseDir, 0, "planned use case %s is not implemented", uc.Name)
+			}
+		}
+	}
+
+	checkPlannedTables(report, plan, artifacts)
+	checkPlannedEvents(report, plan, artifacts)
+	if svc.Exposes(config.InterfaceREST) {
+		checkPlannedEndpoints(report, plan, artifacts)
+	}
+	return report
+}
+
+func checkPlannedTables(report *Report, plan *spec.Plan, artifacts []agents.Artifact) {
+	tables := map[string]bool{}
+	for _, t := range plan.Tables {
+		tables[t.Name] = true
+	}
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".sql") {
+			continue
+		}
+		for _, m := range createTableRe.FindAllStringSubmatchIndex(a.Content, -1) {
+			name := strings.ToLower(strings.ReplaceAll(a.Content[m[2]:m[3]], `"`, ""))
+			if i := strings.LastIndex(name, "."); i >= 0 {
+				name = name[i+1:]
+			}
+			if !tables[name] && !toolTables[name] {
+				line := strings.Count(a.Content[:m[0]], "\n") + 1
+				report.add(SeverityError, a.Filename, line, "table %s is not in the plan", name)
+			}
+		}
+	}
+}
+
+func checkPlannedEvents(report *Report, plan *spec.Plan, artifacts []agents.Artifact) {
+	all, err := postprocess.ParseEventArtifacts(artifacts)
+	if err != nil {
+		return // reported by the domain event check
+	}
+	events, _ := spec.ClassifyEvents(all)
+	planned := map[string]spec.PlanEvent{}
+	for _, e := range plan.Events {
+		planned[e.Name] = e
+	}
+	for _, e := range events {
+		pe, ok := planned[e.Name]
+		if !ok {
+			report.add(SeverityError, e.File, e.Line, "event %s is not in the plan", e.Name)
+			continue
+		}
+		if e.Topic != "" && e.Topic != pe.Topic {
+			report.add(SeverityError, e.File, e.Line, "event %s uses topic %s, plan says %s", e.Name, e.Topic, pe.Topic)
+		}
+	}
+}
+
+func checkPlannedEndpoints(report *Report, plan *spec.Plan, artifacts []agents.Artifact) {
+	routed := make([]bool, len(plan.Endpoints))
+	sawRouter := false
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, "/router.go") && a.Filename != "router.go" {
+			continue
+		}
+		routes, err := ExtractRoutes(a.Filename, a.Content)
+		if err != nil {
+			continue // reported by the OpenAPI check
+		}
+		sawRouter = true
+		for _, r := range routes {
+			if isInfraPath(r.Path) {
+				continue
+			}
+			matched := false
+			for i, ep := range plan.Endpoints {
+				if r.Method != "" && r.Method != strings.ToUpper(ep.Method) {
+					continue
+				}
+				if routeMatches(NormalizePath(r.Path), NormalizePath(ep.Path), []string{""}) {
+					routed[i] = true
+					matched = true
+				}
+			}
+			if !matched {
+				method := r.Method
+				if method == "" {
+					method = "*"
+				}
+				report.add(SeverityError, r.File, r.Line, "route %s %s is not a planned endpoint", method, r.Path)
+			}
+		}
+	}
+	if !sawRouter {
+		return
+	}
+	var missing []string
+	for i, ep := range plan.Endpoints {
+		if !routed[i] {
+			missing = append(missing, strings.ToUpper(ep.Method)+" "+ep.Path)
+		}
+	}
+	sort.Strings(missing)
+	for _, m := range missing {
+		report.add(SeverityWarning, "", 0, "planned endpoint %s has no route", m)
+	}
+}
+
+// plannedForm maps an implementation type name to the planned name it
+// realises: an unexported or *Impl type stands for its exported interface
+func plannedForm(name string) string {
+	name = strings.TrimSuffix(name, "Impl")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func hasUseCaseSuffix(name string) bool {
+	for _, s := range useCaseSuffixes {
+		if strings.HasSuffix(name, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,526 @@
+package verify
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+// protoToken is a lexical token of a .proto file
+type protoToken struct {
+	text string // quoted string literals are stored unquoted
+	line int
+}
+
+type protoFile struct {
+	path      string
+	syntax    string
+	pkg       string
+	pkgLine   int
+	goPackage string
+	imports   []protoRef
+	messages  []*protoMessage
+	enums     []*protoEnum
+	services  []*protoService
+}
+
+type protoRef struct {
+	name string
+	line int
+}
+
+type protoMessage struct {
+	name     string
+	line     int
+	fields   []protoField
+	messages []*protoMessage
+	enums    []*protoEnum
+}
+
+type protoField struct {
+	name   string
+	number int
+	line   int
+}
+
+type protoEnum struct {
+	name   string
+	line   int
+	values []protoField
+}
+
+type protoService struct {
+	name string
+	line int
+	rpcs []protoRPC
+}
+
+type protoRPC struct {
+	name, request, response string
+	line                    int
+}
+
+// LintProtos applies buf STANDARD-style lint rules to every generated .proto
+// file. It is a self-contained Go implementation so it runs without buf or
+// protoc installed.
+func LintProtos(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Protobuf lint"}
+
+	var files []*protoFile
+	for _, a := range artifacts {
+		if !strings.HasSuffix(a.Filename, ".proto") {
+			continue
+		}
+		files = append(files, parseProto(a.Filename, a.Content))
+	}
+	if len(files) == 0 {
+		report.add(SeverityError, "", 0, "no .proto files were generated")
+		return report
+	}
+
+	known := map[string]bool{}
+	for _, f := range files {
+		known[f.path] = true
+	}
+
+	for _, f := range files {
+		lintProtoFile(report, f, known)
+	}
+	return report
+}
+
+var (
+	protoPackageRe = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+	protoVersionRe = regexp.MustCompile(`\.v[0-9]+((alpha|beta)[0-9]*)?(test)?$`)
+	pascalCaseRe   = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	lowerSnakeRe   = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+	upperSnakeRe   = regexp.MustCompile(`^[A-Z][A-Z0-9]*(_[A-Z0-9]+)*$`)
+)
+
+func lintProtoFile(report *Report, f *protoFile, known map[string]bool) {
+	lint := func(rule string, line int, format string, args ...any) {
+		report.add(SeverityError, f.path, line, "["+rule+"] "+format, args...)
+	}
+
+	if f.syntax != "proto3" {
+		lint("SYNTAX_SPECIFIED", 1, `file must declare syntax = "proto3"`)
+	}
+	if f.pkg == "" {
+		lint("PACKAGE_DEFINED", 1, "file must declare a package")
+	} else {
+		if !protoPackageRe.MatchString(f.pkg) {
+			lint("PACKAGE_LOWER_SNAKE_CASE", f.pkgLine, "package %q must be lower_snake_case", f.pkg)
+		}
+		if !protoVersionRe.MatchString(f.pkg) {
+			lint("PACKAGE_VERSION_SUFFIX", f.pkgLine, "package %q must end in a version such as .v1", f.pkg)
+		}
+		if dir := path.Dir(f.path); !strings.HasSuffix(dir, strings.ReplaceAll(f.pkg, ".", "/")) {
+			lint("PACKAGE_DIRECTORY_MATCH", f.pkgLine, "package %q must live in a directory ending in %s, not %s", f.pkg, strings.ReplaceAll(f.pkg, ".", "/"), dir)
+		}
+	}
+	if f.goPackage == "" {
+		report.add(SeverityWarning, f.path, 1, "[GO_PACKAGE] option go_package is not set")
+	}
+
+	for _, imp := range f.imports {
+		if strings.HasPrefix(imp.name, "google/protobuf/") || strings.HasPrefix(imp.name, "google/api/") || strings.HasPrefix(imp.name, "buf/validate/") {
+			continue
+		}
+		resolved := false
+		for k := range known {
+			if strings.HasSuffix(k, imp.name) {
+				resolved = true
+				break
+			}
+		}
+		if !resolved {
+			lint("IMPORT_RESOLVED", imp.line, "import %q does not match any generated .proto file", imp.name)
+		}
+	}
+
+	var lintMessage func(m *protoMessage)
+	lintMessage = func(m *protoMessage) {
+		if !pascalCaseRe.MatchString(m.name) {
+			lint("MESSAGE_PASCAL_CASE", m.line, "message %q must be PascalCase", m.name)
+		}
+		numbers := map[int]string{}
+		for _, fld := range m.fields {
+			if !lowerSnakeRe.MatchString(fld.name) {
+				lint("FIELD_LOWER_SNAKE_CASE", fld.line, "field %s.%s must be lower_snake_case", m.name, fld.name)
+			}
+			if prev, dup := numbers[fld.number]; dup {
+				lint("FIELD_NUMBER_UNIQUE", fld.line, "field %s.%s reuses number %d of %s", m.name, fld.name, fld.number, prev)
+			}
+			numbers[fld.number] = fld.name
+			if fld.number < 1 || fld.number > 536870911 || (fld.number >= 19000 && fld.number <= 19999) {
+				lint("FIELD_NUMBER_VALID", fld.line, "field %s.%s uses invalid or reserved number %d", m.name, fld.name, fld.number)
+			}
+		}
+		for _, e := range m.enums {
+			lintProtoEnum(lint, e)
+		}
+		for _, nested := range m.messages {
+			lintMessage(nested)
+		}
+	}
+	for _, m := range f.messages {
+		lintMessage(m)
+	}
+	for _, e := range f.enums {
+		lintProtoEnum(lint, e)
+	}
+
+	for _, s := range f.services {
+		if !pascalCaseRe.MatchString(s.name) {
+			lint("SERVICE_PASCAL_CASE", s.line, "service %q must be PascalCase", s.name)
+		}
+		if !strings.HasSuffix(s.name, "Service") {
+			lint("SERVICE_SUFFIX", s.line, "service %q must be suffixed with Service", s.name)
+		}
+		used := map[string]string{}
+		for _, rpc := range s.rpcs {
+			if !pascalCaseRe.MatchString(rpc.name) {
+				lint("RPC_PASCAL_CASE", rpc.line, "rpc %q must be PascalCase", rpc.name)
+			}
+			if !standardRPCName(s.name, rpc.name, rpc.request, "Request") {
+				lint("RPC_REQUEST_STANDARD_NAME", rpc.line, "rpc %s request type %s must be named %sRequest", rpc.name, rpc.request, rpc.name)
+			}
+			if !standardRPCName(s.name, rpc.name, rpc.response, "Response") {
+				lint("RPC_RESPONSE_STANDARD_NAME", rpc.line, "rpc %s response type %s must be named %sResponse", rpc.name, rpc.response, rpc.name)
+			}
+			for _, t := range []string{rpc.request, rpc.response} {
+				if prev, dup := used[t]; dup {
+					lint("RPC_REQUEST_RESPONSE_UNIQUE", rpc.line, "rpc %s reuses message %s already used by %s", rpc.name, t, prev)
+				}
+				used[t] = rpc.name
+			}
+		}
+	}
+}
+
+type protoLintFunc func(rule string, line int, format string, args ...any)
+
+func lintProtoEnum(lint protoLintFunc, e *protoEnum) {
+	if !pascalCaseRe.MatchString(e.name) {
+		lint("ENUM_PASCAL_CASE", e.line, "enum %q must be PascalCase", e.name)
+	}
+	prefix := upperSnake(e.name) + "_"
+	for i, v := range e.values {
+		if !upperSnakeRe.MatchString(v.name) {
+			lint("ENUM_VALUE_UPPER_SNAKE_CASE", v.line, "enum value %s must be UPPER_SNAKE_CASE", v.name)
+		}
+		if !strings.HasPrefix(v.name, prefix) {
+			lint("ENUM_VALUE_PREFIX", v.line, "enum value %s must be prefixed with %s", v.name, prefix)
+		}
+		if i == 0 && (v.number != 0 || !strings.HasSuffix(v.name, "_UNSPECIFIED")) {
+			lint("ENUM_ZERO_VALUE_SUFFIX", v.line, "first value of enum %s must be %sUNSPECIFIED = 0", e.name, prefix)
+		}
+	}
+}
+
+// standardRPCName accepts <Rpc><Suffix> and <Service><Rpc><Suffix>, as buf does
+func standardRPCName(service, rpc, typ, suffix string) bool {
+	typ = typ[strings.LastIndex(typ, ".")+1:]
+	return typ == rpc+suffix || typ == service+rpc+suffix || typ == strings.TrimSuffix(service, "Service")+rpc+suffix
+}
+
+// upperSnake converts PascalCase to UPPER_SNAKE_CASE
+func upperSnake(s string) string {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
+
+// tokenizeProto splits a .proto source into tokens, dropping comments.
+// Dotted names (google.protobuf.Timestamp) are kept as a single token.
+func tokenizeProto(src string) []protoToken {
+	var toks []protoToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src) - i - 2
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			toks = append(toks, protoToken{text: src[i+1 : min(j, len(src))], line: line})
+			i = j + 1
+		case isProtoIdentChar(c):
+			j := i
+			for j < len(src) && isProtoIdentChar(src[j]) {
+				j++
+			}
+			toks = append(toks, protoToken{text: src[i:j], line: line})
+			i = j
+		default:
+			toks = append(toks, protoToken{text: string(c), line: line})
+			i++
+		}
+	}
+	return toks
+}
+
+func isProtoIdentChar(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// protoParser is a tolerant recursive-descent parser over protoTokens. It
+// extracts declarations needed for linting and skips everything else.
+type protoParser struct {
+	toks []protoToken
+	pos  int
+}
+
+func (p *protoParser) peek() protoToken {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return protoToken{}
+}
+
+func (p *protoParser) next() protoToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *protoParser) done() bool { return p.pos >= len(p.toks) }
+
+// skipStatement advances past the next ';' at the current nesting level,
+// or past a balanced {...} block if one opens first. Message literals inside
+// [...] field options do not end the statement.
+func (p *protoParser) skipStatement() {
+	depth := 0 // open '[', and '{' inside them
+	for !p.done() {
+		switch p.next().text {
+		case ";":
+			if depth == 0 {
+				return
+			}
+		case "[":
+			depth++
+		case "]":
+			depth--
+		case "{":
+			if depth == 0 {
+				p.skipBlock()
+				return
+			}
+			depth++
+		case "}":
+			depth--
+		}
+	}
+}
+
+// skipBlock advances past the '}' matching an already consumed '{'
+func (p *protoParser) skipBlock() {
+	depth := 1
+	for !p.done() && depth > 0 {
+		switch p.next().text {
+		case "{":
+			depth++
+		case "}":
+			depth--
+		}
+	}
+}
+
+func parseProto(filename, src string) *protoFile {
+	f := &protoFile{path: filename}
+	p := &protoParser{toks: tokenizeProto(src)}
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "syntax", "edition":
+			p.next() // =
+			f.syntax = p.next().text
+			p.skipStatement()
+		case "package":
+			f.pkg, f.pkgLine = p.next().text, t.line
+			p.skipStatement()
+		case "import":
+			if n := p.peek().text; n == "public" || n == "weak" {
+				p.next()
+			}
+			f.imports = append(f.imports, protoRef{name: p.next().text, line: t.line})
+			p.skipStatement()
+		case "option":
+			name := p.next().text
+			p.next() // =
+			val := p.next()
+			if name == "go_package" {
+				f.goPackage = val.text
+			}
+			p.skipStatement()
+		case "message":
+			f.messages = append(f.messages, p.parseMessage(t.line))
+		case "enum":
+			f.enums = append(f.enums, p.parseEnum(t.line))
+		case "service":
+			f.services = append(f.services, p.parseService(t.line))
+		case ";":
+		default:
+			p.skipStatement()
+		}
+	}
+	return f
+}
+
+func (p *protoParser) parseMessage(line int) *protoMessage {
+	m := &protoMessage{name: p.next().text, line: line}
+	if p.next().text != "{" {
+		return m
+	}
+	p.parseMessageBody(m)
+	return m
+}
+
+func (p *protoParser) parseMessageBody(m *protoMessage) {
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			return
+		case ";":
+		case "message":
+			m.messages = append(m.messages, p.parseMessage(t.line))
+		case "enum":
+			m.enums = append(m.enums, p.parseEnum(t.line))
+		case "oneof":
+			p.next() // name
+			if p.next().text == "{" {
+				p.parseMessageBody(m)
+			}
+		case "option", "reserved", "extensions", "extend":
+			p.skipStatement()
+		case "map":
+			// map<K, V> name = N;
+			for !p.done() && p.next().text != ">" {
+			}
+			p.parseFieldTail(m, p.next())
+		case "repeated", "optional", "required":
+			p.next() // type
+			p.parseFieldTail(m, p.next())
+		default:
+			// t is the field type
+			p.parseFieldTail(m, p.next())
+		}
+	}
+}
+
+// parseFieldTail parses "= N [options];" after a field name token
+func (p *protoParser) parseFieldTail(m *protoMessage, name protoToken) {
+	if p.peek().text != "=" {
+		p.skipStatement()
+		return
+	}
+	p.next()
+	n, _ := strconv.Atoi(p.next().text)
+	m.fields = append(m.fields, protoField{name: name.text, number: n, line: name.line})
+	p.skipStatement()
+}
+
+func (p *protoParser) parseEnum(line int) *protoEnum {
+	e := &protoEnum{name: p.next().text, line: line}
+	if p.next().text != "{" {
+		return e
+	}
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			return e
+		case ";":
+		case "option", "reserved":
+			p.skipStatement()
+		default:
+			if p.peek().text != "=" {
+				p.skipStatement()
+				continue
+			}
+			p.next()
+			num := p.next().text
+			if num == "-" {
+				num = "-" + p.next().text
+			}
+			n, _ := strconv.Atoi(num)
+			e.values = append(e.values, protoField{name: t.text, number: n, line: t.line})
+			p.skipStatement()
+		}
+	}
+	return e
+}
+
+func (p *protoParser) parseService(line int) *protoService {
+	s := &protoService{name: p.next().text, line: line}
+	if p.next().text != "{" {
+		return s
+	}
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			return s
+		case "rpc":
+			rpc := protoRPC{name: p.next().text, line: t.line}
+			rpc.request = p.parseRPCType()
+			if p.peek().text == "returns" {
+				p.next()
+			}
+			rpc.response = p.parseRPCType()
+			s.rpcs = append(s.rpcs, rpc)
+			p.skipStatement()
+		case ";":
+		default:
+			p.skipStatement()
+		}
+	}
+	return s
+}
+
+// parseRPCType parses "( [stream] Type )"
+func (p *protoParser) parseRPCType() string {
+	if p.peek().text != "(" {
+		return ""
+	}
+	p.next()
+	typ := p.next().text
+	if typ == "stream" {
+		typ = p.next().text
+	}
+	if p.peek().text == ")" {
+		p.next()
+	}
+	return typ
+}
@@ -0,0 +1,102 @@
+// Package verify holds deterministic checks that run over agent artifacts
+// after generation. Checks never call the Claude API; they parse what the
+// agents produced and report findings.
+package verify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+// Severity classifies how serious a finding is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding is a single issue reported by a check
+type Finding struct {
+	Severity Severity
+	File     string
+	Line     int
+	Message  string
+}
+
+// Report groups the findings of one check
+type Report struct {
+	Check    string
+	Findings []Finding
+}
+
+// Passed reports whether the check produced no error-level findings
+func (r *Report) Passed() bool {
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of findings with the given severity
+func (r *Report) Count(sev Severity) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(sev Severity, file string, line int, format string, args ...any) {
+	r.Findings = append(r.Findings, Finding{
+		Severity: sev,
+		File:     file,
+		Line:     line,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Markdown renders the report as a markdown section
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+	status := "✅ passed"
+	if !r.Passed() {
+		status = "❌ failed"
+	}
+	sb.WriteString(fmt.Sprintf("## %s — %s\n\n", r.Check, status))
+	if len(r.Findings) == 0 {
+		sb.WriteString("No findings.\n\n")
+		return sb.String()
+	}
+	sb.WriteString("| Severity | Location | Message |\n|---|---|---|\n")
+	for _, f := range r.Findings {
+		loc := f.File
+		if f.Line > 0 {
+			loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if loc == "" {
+			loc = "-"
+		}
+		msg := strings.ReplaceAll(f.Message, "|", `\|`)
+		sb.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", f.Severity, loc, msg))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// findArtifact returns the first artifact whose filename ends with suffix
+func findArtifact(artifacts []agents.Artifact, suffix string) (agents.Artifact, bool) {
+	for _, a := range artifacts {
+		if strings.HasSuffix(a.Filename, suffix) {
+			return a, true
+		}
+	}
+	return agents.Artifact{}, false
+}
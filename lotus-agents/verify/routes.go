@@ -0,0 +1,255 @@
+package verify
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Route is an HTTP route registered in generated router code
+type Route struct {
+	Method string // upper-case; "" when the route accepts any method
+	Path   string
+	File   string
+	Line   int
+}
+
+// routeMethods maps router method names (chi, gin/echo) to HTTP methods
+var routeMethods = map[string]string{
+	"Get": "GET", "Post": "POST", "Put": "PUT", "Patch": "PATCH", "Delete": "DELETE",
+	"Head": "HEAD", "Options": "OPTIONS",
+	"GET": "GET", "POST": "POST", "PUT": "PUT", "PATCH": "PATCH", "DELETE": "DELETE",
+	"HEAD": "HEAD", "OPTIONS": "OPTIONS",
+}
+
+// ExtractRoutes parses Go router source and returns the routes it registers.
+// It understands chi (Get/Post/Method/Route/Group/Mount), net/http 1.22
+// patterns ("GET /path"), gorilla/mux (.Methods) and gin/echo upper-case
+// helpers. Routes built from non-literal paths are skipped.
+func ExtractRoutes(filename, src string) ([]Route, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	// Mount prefixes apply to routes of a mounted sub-router variable, or to
+	// every route of the function or method that builds the mounted router
+	funcs := map[string]string{}
+	for _, decl := range file.Decls {
+		collectMounts(decl, "", map[string]string{}, funcs)
+	}
+	var routes []Route
+	for _, decl := range file.Decls {
+		prefix := ""
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			prefix = funcs[fn.Name.Name]
+		}
+		vars := map[string]string{}
+		collectMounts(decl, prefix, vars, map[string]string{})
+		collectRoutes(fset, decl, prefix, vars, &routes)
+	}
+	for i := range routes {
+		routes[i].File = filename
+	}
+	return routes, nil
+}
+
+// collectMounts records chi r.Mount("/prefix", sub) calls: vars maps a
+// mounted sub-router variable to its prefix, funcs maps the function or
+// method producing a mounted router (r.Mount("/prefix", h.Routes()))
+func collectMounts(root ast.Node, prefix string, vars, funcs map[string]string) {
+	ast.Inspect(root, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		p, ok := stringLit(call.Args[0])
+		if !ok {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "Route":
+			if fn, ok := call.Args[1].(*ast.FuncLit); ok {
+				collectMounts(fn.Body, joinPath(prefix, p), vars, funcs)
+				return false
+			}
+		case "Mount":
+			switch sub := call.Args[1].(type) {
+			case *ast.Ident:
+				vars[sub.Name] = joinPath(prefix, p)
+			case *ast.CallExpr:
+				switch fn := sub.Fun.(type) {
+				case *ast.Ident:
+					funcs[fn.Name] = joinPath(prefix, p)
+				case *ast.SelectorExpr:
+					funcs[fn.Sel.Name] = joinPath(prefix, p)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// routerPrefix returns the mount prefix of a mounted sub-router receiver
+func routerPrefix(x ast.Expr, prefix string, vars map[string]string) string {
+	if id, ok := x.(*ast.Ident); ok {
+		if p, ok := vars[id.Name]; ok {
+			return p
+		}
+	}
+	return prefix
+}
+
+func collectRoutes(fset *token.FileSet, root ast.Node, prefix string, vars map[string]string, routes *[]Route) {
+	ast.Inspect(root, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		line := fset.Position(call.Pos()).Line
+		name := sel.Sel.Name
+		base := routerPrefix(sel.X, prefix, vars)
+
+		switch {
+		case name == "Route" && len(call.Args) == 2:
+			// chi: r.Route("/prefix", func(r chi.Router) { ... })
+			p, ok := stringLit(call.Args[0])
+			fn, isFn := call.Args[1].(*ast.FuncLit)
+			if ok && isFn {
+				collectRoutes(fset, fn.Body, joinPath(base, p), vars, routes)
+				return false
+			}
+		case name == "Methods":
+			// gorilla: r.HandleFunc("/path", h).Methods("GET", "POST")
+			inner, ok := sel.X.(*ast.CallExpr)
+			if !ok || len(inner.Args) == 0 {
+				return true
+			}
+			if innerSel, ok := inner.Fun.(*ast.SelectorExpr); ok {
+				base = routerPrefix(innerSel.X, prefix, vars)
+			}
+			p, ok := stringLit(inner.Args[0])
+			if !ok {
+				return true
+			}
+			for _, arg := range call.Args {
+				if m, ok := methodArg(arg); ok {
+					*routes = append(*routes, Route{Method: m, Path: joinPath(base, p), Line: line})
+				}
+			}
+			return false
+		case name == "Method" || name == "MethodFunc":
+			// chi: r.Method("GET", "/path", h)
+			if len(call.Args) >= 2 {
+				m, mok := methodArg(call.Args[0])
+				p, pok := stringLit(call.Args[1])
+				if mok && pok {
+					*routes = append(*routes, Route{Method: m, Path: joinPath(base, p), Line: line})
+				}
+			}
+		case name == "Handle" || name == "HandleFunc":
+			// net/http: mux.HandleFunc("GET /path/{id}", h)
+			if len(call.Args) >= 2 {
+				if p, ok := stringLit(call.Args[0]); ok {
+					method := ""
+					if m, rest, found := strings.Cut(p, " "); found && isHTTPMethod(m) {
+						method, p = m, strings.TrimSpace(rest)
+					}
+					*routes = append(*routes, Route{Method: method, Path: joinPath(base, p), Line: line})
+				}
+			}
+		default:
+			if method, ok := routeMethods[name]; ok && len(call.Args) >= 2 {
+				if p, ok := stringLit(call.Args[0]); ok && strings.HasPrefix(p, "/") {
+					*routes = append(*routes, Route{Method: method, Path: joinPath(base, p), Line: line})
+				}
+			}
+		}
+		return true
+	})
+}
+
+func stringLit(e ast.Expr) (string, bool) {
+	lit, ok := e.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// methodArg accepts "GET" literals and http.MethodGet selectors
+func methodArg(e ast.Expr) (string, bool) {
+	if s, ok := stringLit(e); ok {
+		s = strings.ToUpper(s)
+		return s, isHTTPMethod(s)
+	}
+	if sel, ok := e.(*ast.SelectorExpr); ok {
+		if m, ok := strings.CutPrefix(sel.Sel.Name, "Method"); ok {
+			m = strings.ToUpper(m)
+			return m, isHTTPMethod(m)
+		}
+	}
+	return "", false
+}
+
+func isHTTPMethod(s string) bool {
+	switch s {
+	case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE", "CONNECT":
+		return true
+	}
+	return false
+}
+
+func joinPath(prefix, p string) string {
+	if prefix == "" {
+		return p
+	}
+	if p == "/" || p == "" {
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(p, "/")
+}
+
+var (
+	bracePathParam = regexp.MustCompile(`\{[^}]*\}`)
+	colonPathParam = regexp.MustCompile(`/:[^/]+`)
+)
+
+// NormalizePath reduces a route or spec path to a comparable form: every
+// path parameter becomes "{}" and trailing slashes are dropped.
+func NormalizePath(p string) string {
+	p = bracePathParam.ReplaceAllString(p, "{}")
+	p = colonPathParam.ReplaceAllString(p, "/{}")
+	if len(p) > 1 {
+		p = strings.TrimSuffix(p, "/")
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return p
+}
+
+// isInfraPath reports whether a route is an operational endpoint that is
+// not expected to appear in the API contract
+func isInfraPath(p string) bool {
+	switch strings.TrimSuffix(p, "/") {
+	case "/health", "/healthz", "/livez", "/readyz", "/ready", "/metrics", "/ping":
+		return true
+	}
+	return strings.HasPrefix(p, "/debug/") || strings.HasPrefix(p, "/swagger")
+}
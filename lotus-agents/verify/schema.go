@@ -0,0 +1,180 @@
+package verify
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed schemas/kubernetes.json
+var kubernetesSchemaJSON []byte
+
+// jsonSchema is the subset of JSON Schema used by the bundled Kubernetes
+// OpenAPI definitions
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format"`
+	Enum                 []string               `json:"enum"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Items                *jsonSchema            `json:"items"`
+	AdditionalProperties *additionalProperties  `json:"additionalProperties"`
+}
+
+// additionalProperties is either false (closed object) or a schema for map values
+type additionalProperties struct {
+	Closed bool
+	Schema *jsonSchema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		a.Closed = !b
+		return nil
+	}
+	a.Schema = &jsonSchema{}
+	return json.Unmarshal(data, a.Schema)
+}
+
+// schemaBundle maps "<apiVersion>/<kind>" to definitions
+type schemaBundle struct {
+	Kinds       map[string]string      `json:"kinds"`
+	Definitions map[string]*jsonSchema `json:"definitions"`
+}
+
+var kubernetesSchemas = sync.OnceValues(func() (*schemaBundle, error) {
+	b := &schemaBundle{}
+	if err := json.Unmarshal(kubernetesSchemaJSON, b); err != nil {
+		return nil, fmt.Errorf("bundled kubernetes schema: %w", err)
+	}
+	return b, nil
+})
+
+// lookup returns the definition for an apiVersion and kind
+func (b *schemaBundle) lookup(apiVersion, kind string) (*jsonSchema, bool) {
+	name, ok := b.Kinds[apiVersion+"/"+kind]
+	if !ok {
+		return nil, false
+	}
+	s, ok := b.Definitions[name]
+	return s, ok
+}
+
+func (b *schemaBundle) resolve(s *jsonSchema) *jsonSchema {
+	for s != nil && s.Ref != "" {
+		s = b.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+	}
+	return s
+}
+
+// validate checks a decoded YAML value against s and returns one message per violation
+func (b *schemaBundle) validate(s *jsonSchema, v any, at string) []string {
+	s = b.resolve(s)
+	if s == nil || v == nil {
+		return nil
+	}
+	where := at
+	if where == "" {
+		where = "(root)"
+	}
+
+	var errs []string
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected object, got %s", where, yamlTypeName(v))}
+		}
+		for _, r := range s.Required {
+			if _, ok := obj[r]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing required field %q", where, r))
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := joinSchemaPath(at, k)
+			switch {
+			case s.Properties[k] != nil:
+				errs = append(errs, b.validate(s.Properties[k], obj[k], child)...)
+			case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+				errs = append(errs, b.validate(s.AdditionalProperties.Schema, obj[k], child)...)
+			case s.AdditionalProperties != nil && s.AdditionalProperties.Closed:
+				errs = append(errs, fmt.Sprintf("%s: unknown field %q", where, k))
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array, got %s", where, yamlTypeName(v))}
+		}
+		for i, item := range arr {
+			errs = append(errs, b.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		switch v.(type) {
+		case string:
+		case int, float64:
+			if s.Format != "quantity" && s.Format != "int-or-string" {
+				return []string{fmt.Sprintf("%s: expected string, got %s", where, yamlTypeName(v))}
+			}
+			if f, ok := v.(float64); ok && s.Format == "int-or-string" && f != math.Trunc(f) {
+				return []string{fmt.Sprintf("%s: expected integer or string, got %v", where, f)}
+			}
+		default:
+			return []string{fmt.Sprintf("%s: expected string, got %s", where, yamlTypeName(v))}
+		}
+		if str, ok := v.(string); ok && len(s.Enum) > 0 && !contains(s.Enum, str) {
+			errs = append(errs, fmt.Sprintf("%s: %q is not one of %s", where, str, strings.Join(s.Enum, ", ")))
+		}
+	case "integer":
+		if _, ok := v.(int); !ok {
+			return []string{fmt.Sprintf("%s: expected integer, got %s", where, yamlTypeName(v))}
+		}
+	case "number":
+		switch v.(type) {
+		case int, float64:
+		default:
+			return []string{fmt.Sprintf("%s: expected number, got %s", where, yamlTypeName(v))}
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return []string{fmt.Sprintf("%s: expected boolean, got %s", where, yamlTypeName(v))}
+		}
+	}
+	return errs
+}
+
+func joinSchemaPath(at, key string) string {
+	if at == "" {
+		return key
+	}
+	return at + "." + key
+}
+
+func yamlTypeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case int:
+		return "integer"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
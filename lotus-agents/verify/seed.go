@@ -0,0 +1,233 @@
+package verify
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
+)
+
+// CheckSeedData parses the seed SQL against the schema the up migrations
+// build: every INSERT must target an existing table and columns, supply all
+// required columns, insert referenced rows first (rows the migrations insert,
+// such as lookup values, count as inserted) and be re-runnable; the Makefile
+// must declare a seed target and fixture builders must parse
+func CheckSeedData(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Seed data"}
+
+	schema := MigratedSchema(artifacts)
+	seeds := sqlFilesIn(artifacts, agents.SeedDir)
+	if len(seeds) == 0 {
+		report.add(SeverityError, agents.SeedDir, 0, "no seed data was generated")
+	}
+
+	seeded := map[string]*seededTable{}
+	for _, a := range sqlFilesIn(artifacts, agents.MigrationDir) {
+		if agents.IsDownMigration(a.Filename) {
+			continue
+		}
+		for _, stmt := range spec.SplitSQL(a.Content) {
+			if ins, ok := spec.ParseInsert(stmt); ok {
+				checkInsert(&Report{}, a.Filename, schema, seeded, ins) // only the seed files are checked
+			}
+		}
+	}
+	for _, a := range seeds {
+		for _, stmt := range spec.SplitSQL(a.Content) {
+			ins, ok := spec.ParseInsert(stmt)
+			if !ok {
+				continue
+			}
+			checkInsert(report, a.Filename, schema, seeded, ins)
+		}
+	}
+
+	for _, a := range artifacts {
+		if path.Base(a.Filename) == "Makefile" && !spec.ParseMakefile(a.Content).Has("seed") {
+			report.add(SeverityError, a.Filename, 0, "no seed target")
+		}
+	}
+
+	fixtures := 0
+	for _, a := range artifacts {
+		if !strings.Contains(a.Filename, agents.FixtureDir) || !strings.HasSuffix(a.Filename, ".go") {
+			continue
+		}
+		fixtures++
+		if _, err := parser.ParseFile(token.NewFileSet(), a.Filename, a.Content, 0); err != nil {
+			report.add(SeverityError, a.Filename, 0, "cannot parse: %v", err)
+		}
+	}
+	if fixtures == 0 {
+		report.add(SeverityWarning, agents.FixtureDir, 0, "no Go fixture builders were generated")
+	}
+	return report
+}
+
+// MigratedSchema applies the up migrations in file name order and returns the resulting schema
+func MigratedSchema(artifacts []agents.Artifact) *spec.SQLSchema {
+	schema := spec.NewSQLSchema()
+	for _, a := range sqlFilesIn(artifacts, agents.MigrationDir) {
+		if agents.IsDownMigration(a.Filename) {
+			continue
+		}
+		for _, stmt := range spec.SplitSQL(a.Content) {
+			_ = schema.Apply(stmt) // invalid DDL is reported by the migration check
+		}
+	}
+	return schema
+}
+
+// sqlFilesIn returns the .sql artifacts under dir sorted by file name
+func sqlFilesIn(artifacts []agents.Artifact, dir string) []agents.Artifact {
+	var files []agents.Artifact
+	for _, a := range artifacts {
+		if strings.Contains(a.Filename, dir) && strings.HasSuffix(a.Filename, ".sql") {
+			files = append(files, a)
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool { return path.Base(files[i].Filename) < path.Base(files[j].Filename) })
+	return files
+}
+
+// seededTable records the literal values seeded per column; partial is set
+// for a column once a row supplies it through an expression or a default
+type seededTable struct {
+	values  map[string]map[string]bool
+	partial map[string]bool
+}
+
+func checkInsert(report *Report, file string, schema *spec.SQLSchema, seeded map[string]*seededTable, ins *spec.SQLInsert) {
+	t, ok := schema.Tables[ins.Table]
+	if !ok {
+		report.add(SeverityError, file, ins.Line, "inserts into %s, which the migrations do not create", ins.Table)
+		return
+	}
+	if !ins.OnConflict {
+		report.add(SeverityWarning, file, ins.Line, "INSERT into %s has no ON CONFLICT clause, so make seed cannot be re-run", ins.Table)
+	}
+
+	cols := ins.Columns
+	if len(cols) == 0 {
+		report.add(SeverityWarning, file, ins.Line, "INSERT into %s lists no columns", ins.Table)
+		for _, c := range t.Columns {
+			cols = append(cols, c.Name)
+		}
+	}
+	listed := map[string]bool{}
+	for _, c := range cols {
+		listed[c] = true
+		if t.Column(c) == nil {
+			report.add(SeverityError, file, ins.Line, "%s has no column %s", ins.Table, c)
+		}
+	}
+	for _, c := range t.Columns {
+		if c.NotNull && !c.HasDefault && !listed[c.Name] {
+			report.add(SeverityError, file, ins.Line, "INSERT into %s omits %s, which is NOT NULL without a default", ins.Table, c.Name)
+		}
+	}
+
+	reported := map[string]bool{}
+	st := seeded[ins.Table]
+	if st == nil {
+		st = &seededTable{values: map[string]map[string]bool{}, partial: map[string]bool{}}
+	}
+	for _, row := range ins.Rows {
+		if len(row) != len(cols) {
+			report.add(SeverityError, file, ins.Line, "INSERT into %s has %d values for %d columns", ins.Table, len(row), len(cols))
+			continue
+		}
+		values := map[string]string{}
+		for i, c := range cols {
+			lit, isNull, isLit := sqlLiteral(row[i])
+			if isNull {
+				if col := t.Column(c); col != nil && col.NotNull {
+					report.add(SeverityError, file, ins.Line, "inserts NULL into %s.%s, which is NOT NULL", ins.Table, c)
+				}
+				continue
+			}
+			if isLit {
+				values[c] = lit
+			} else {
+				values[c] = "?"
+			}
+		}
+		for _, fk := range t.ForeignKeys {
+			if msg := checkSeedReference(ins, fk, values, schema, seeded); msg != "" && !reported[msg] {
+				reported[msg] = true
+				report.add(SeverityError, file, ins.Line, "%s", msg)
+			}
+		}
+		for _, c := range t.Columns {
+			v, ok := values[c.Name]
+			if !ok || v == "?" {
+				st.partial[c.Name] = true
+				continue
+			}
+			if st.values[c.Name] == nil {
+				st.values[c.Name] = map[string]bool{}
+			}
+			st.values[c.Name][v] = true
+		}
+	}
+	if len(ins.Rows) == 0 {
+		for _, c := range t.Columns {
+			st.partial[c.Name] = true // INSERT ... SELECT
+		}
+	}
+	seeded[ins.Table] = st
+}
+
+// checkSeedReference describes a foreign key value whose referenced row has
+// not been seeded yet, or returns "" when the reference holds or cannot be told
+func checkSeedReference(ins *spec.SQLInsert, fk spec.SQLForeignKey, values map[string]string, schema *spec.SQLSchema, seeded map[string]*seededTable) string {
+	for _, c := range fk.Columns {
+		if _, ok := values[c]; !ok {
+			return "" // NULL or omitted: nothing referenced
+		}
+	}
+	if fk.RefTable == ins.Table {
+		return ""
+	}
+	ref, ok := seeded[fk.RefTable]
+	if !ok {
+		return fmt.Sprintf("%s rows reference %s before any %s row is seeded", ins.Table, fk.RefTable, fk.RefTable)
+	}
+	if len(fk.Columns) != 1 {
+		return ""
+	}
+	refCol := ""
+	if len(fk.RefColumns) == 1 {
+		refCol = fk.RefColumns[0]
+	} else if rt, ok := schema.Tables[fk.RefTable]; ok && len(rt.PrimaryKey) == 1 {
+		refCol = rt.PrimaryKey[0]
+	}
+	v := values[fk.Columns[0]]
+	if refCol == "" || v == "?" || ref.partial[refCol] || ref.values[refCol][v] {
+		return ""
+	}
+	return fmt.Sprintf("%s.%s = %s matches no seeded %s.%s", ins.Table, fk.Columns[0], v, fk.RefTable, refCol)
+}
+
+// sqlLiteral returns the value of a string, number or NULL literal, with any
+// ::type cast removed; ok is false for any other expression
+func sqlLiteral(expr string) (value string, isNull, ok bool) {
+	expr = strings.TrimSpace(expr)
+	if i := strings.LastIndex(expr, "::"); i > 0 && !strings.Contains(expr[i:], "'") {
+		expr = strings.TrimSpace(expr[:i])
+	}
+	switch {
+	case strings.EqualFold(expr, "null"):
+		return "", true, true
+	case len(expr) >= 2 && expr[0] == '\'' && expr[len(expr)-1] == '\'':
+		return strings.ReplaceAll(expr[1:len(expr)-1], "''", "'"), false, true
+	case expr != "" && strings.Trim(expr, "0123456789.-") == "":
+		return expr, false, true
+	}
+	return "", false, false
+}
@@ -0,0 +1,82 @@
+package verify
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Deathstroke72/black-lotus/lotus-agents/agents"
+)
+
+var (
+	// backtickPathRe matches a backtick-quoted relative file path such as `internal/adapter/http/middleware/auth.go`
+	backtickPathRe = regexp.MustCompile("`([\\w.-]+(?:/[\\w.-]+)*\\.[A-Za-z]+|[\\w.-]+/[\\w./-]+)`")
+
+	openRisksRe = regexp.MustCompile(`(?im)^#+\s*open risks`)
+)
+
+// CheckThreatModel verifies THREAT_MODEL.md: a Mermaid data-flow diagram, a
+// STRIDE table covering every category, an open risks section, and
+// mitigations that cite files the pipeline actually generated
+func CheckThreatModel(artifacts []agents.Artifact) *Report {
+	report := &Report{Check: "Threat model"}
+
+	art, ok := findArtifact(artifacts, agents.ThreatModelPath)
+	if !ok {
+		report.add(SeverityError, agents.ThreatModelPath, 0, "no threat model was generated")
+		return report
+	}
+
+	if !strings.Contains(art.Content, "```mermaid") {
+		report.add(SeverityWarning, art.Filename, 0, "no Mermaid data-flow diagram")
+	}
+	if !openRisksRe.MatchString(art.Content) {
+		report.add(SeverityWarning, art.Filename, 0, "no Open Risks section")
+	}
+
+	generated := map[string]bool{}
+	for _, a := range artifacts {
+		generated[strings.TrimPrefix(a.Filename, "./")] = true
+	}
+
+	covered := map[string]bool{}
+	rows := 0
+	for i, line := range strings.Split(art.Content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") || strings.HasPrefix(line, "|-") || strings.HasPrefix(line, "| -") {
+			continue
+		}
+		category := ""
+		for _, c := range agents.STRIDECategories {
+			if strings.Contains(strings.ToLower(line), "| "+strings.ToLower(c)+" |") {
+				category = c
+			}
+		}
+		if category == "" {
+			continue
+		}
+		rows++
+		covered[category] = true
+		for _, m := range backtickPathRe.FindAllStringSubmatch(line, -1) {
+			path := strings.TrimPrefix(m[1], "./")
+			if !generated[path] && !hasSuffixArtifact(artifacts, "/"+path) {
+				report.add(SeverityWarning, art.Filename, i+1, "mitigation cites %s, which was not generated", path)
+			}
+		}
+	}
+
+	if rows == 0 {
+		report.add(SeverityError, art.Filename, 0, "no STRIDE table rows found")
+		return report
+	}
+	for _, c := range agents.STRIDECategories {
+		if !covered[c] {
+			report.add(SeverityError, art.Filename, 0, "STRIDE category %q has no threats", c)
+		}
+	}
+	return report
+}
+
+func hasSuffixArtifact(artifacts []agents.Artifact, suffix string) bool {
+	_, ok := findArtifact(artifacts, suffix)
+	return ok
+}
@@ -2,15 +2,26 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/Deathstroke72/black-lotus/lotus-agents/config"
 	"github.com/Deathstroke72/black-lotus/lotus-agents/orchestrator"
+	"github.com/Deathstroke72/black-lotus/lotus-agents/spec"
 )
 
 func main() {
+	openapiFile := flag.String("openapi", "", "existing OpenAPI document to generate from (contract-first mode)")
+	ciProvider := flag.String("ci", "", "CI system to generate definitions for: github or gitlab (default github)")
+	modulePath := flag.String("module", "", "Go module path of the generated service (default github.com/example/<name>)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [output-dir]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
 	cfg := config.Load()
 
 	if cfg.AnthropicAPIKey == "" {
@@ -34,9 +45,32 @@ func main() {
 	// ---------------------------------------------------------------
 	svc := config.InventoryService()
 
+	if *openapiFile != "" {
+		data, err := os.ReadFile(*openapiFile)
+		if err != nil {
+			log.Fatalf("Failed to read OpenAPI document: %v", err)
+		}
+		if _, err := spec.ParseOpenAPI(data); err != nil {
+			log.Fatalf("Invalid OpenAPI document %s: %v", *openapiFile, err)
+		}
+		svc.OpenAPISpec = string(data)
+	}
+
+	switch *ciProvider {
+	case "":
+	case config.CIGitHubActions, config.CIGitLab:
+		svc.CIProvider = *ciProvider
+	default:
+		log.Fatalf("Unknown CI provider %q (want %s or %s)", *ciProvider, config.CIGitHubActions, config.CIGitLab)
+	}
+
+	if *modulePath != "" {
+		svc.ModulePath = *modulePath
+	}
+
 	outputDir := "../generated"
-	if len(os.Args) > 1 {
-		outputDir = os.Args[1]
+	if flag.NArg() > 0 {
+		outputDir = flag.Arg(0)
 	}
 
 	ctx := context.Background()
@@ -44,7 +78,14 @@ func main() {
 	fmt.Printf("🚀 Microservice Agent Pipeline\n")
 	fmt.Printf("   Service:  %s\n", svc.Name)
 	fmt.Printf("   Language: %s\n", svc.Language)
-	fmt.Printf("   Output:   %s\n\n", outputDir)
+	fmt.Printf("   Output:   %s\n", outputDir)
+	if svc.IsGo() {
+		fmt.Printf("   Module:   %s\n", svc.Module())
+	}
+	if svc.ContractFirst() {
+		fmt.Printf("   Contract: %s\n", *openapiFile)
+	}
+	fmt.Println()
 
 	pipeline := orchestrator.NewPipeline(cfg)
 